@@ -11,7 +11,12 @@
 package cpuid
 
 import (
+	"encoding/binary"
+	"fmt"
 	"math"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -36,8 +41,104 @@ const (
 	Hygon
 	SiS
 	RDC
+	Zhaoxin
+	Cyrix
+	QEMU         // QEMU TCG (software emulation)
+	ACRN         // Project ACRN hypervisor
+	Parallels    // Parallels Desktop
+	QNX          // QNX Hypervisor
+	AppleRosetta // Apple Rosetta 2 x86 translation
+	MicrosoftXTA // Microsoft x86 emulation on ARM
+	ARM          // Arm Limited
+	Ampere       // Ampere Computing
+	Apple        // Apple Silicon
+	Fujitsu      // Fujitsu A64FX
+	HiSilicon    // HiSilicon Kunpeng
+	Qualcomm     // Qualcomm Kryo, Falkor and Oryon
+	NVIDIA       // NVIDIA Denver and Carmel
 )
 
+// vendorNames contains the names of all known vendors, indexed by Vendor.
+// Vendors added by RegisterVendor are appended.
+var vendorNames = []string{
+	Other:        "Other",
+	Intel:        "Intel",
+	AMD:          "AMD",
+	VIA:          "VIA",
+	Transmeta:    "Transmeta",
+	NSC:          "NSC",
+	KVM:          "KVM",
+	MSVM:         "MSVM",
+	VMware:       "VMware",
+	XenHVM:       "XenHVM",
+	Bhyve:        "Bhyve",
+	Hygon:        "Hygon",
+	SiS:          "SiS",
+	RDC:          "RDC",
+	Zhaoxin:      "Zhaoxin",
+	Cyrix:        "Cyrix",
+	QEMU:         "QEMU",
+	ACRN:         "ACRN",
+	Parallels:    "Parallels",
+	QNX:          "QNX",
+	AppleRosetta: "AppleRosetta",
+	MicrosoftXTA: "MicrosoftXTA",
+	ARM:          "ARM",
+	Ampere:       "Ampere",
+	Apple:        "Apple",
+	Fujitsu:      "Fujitsu",
+	HiSilicon:    "HiSilicon",
+	Qualcomm:     "Qualcomm",
+	NVIDIA:       "NVIDIA",
+}
+
+// String returns the name of the vendor.
+func (v Vendor) String() string {
+	if v >= 0 && int(v) < len(vendorNames) {
+		return vendorNames[v]
+	}
+	return fmt.Sprintf("Vendor(%d)", int(v))
+}
+
+// ParseVendor returns the vendor with the given name or CPUID vendor signature.
+// Names are matched case insensitively.
+// Other is returned if the vendor is unknown.
+func ParseVendor(s string) Vendor {
+	for i, name := range vendorNames {
+		if strings.EqualFold(name, s) {
+			return Vendor(i)
+		}
+	}
+	if v, ok := vendorMapping[s]; ok {
+		return v
+	}
+	return Other
+}
+
+// RegisterVendor adds a CPUID vendor signature, mapping it to the vendor with the given name.
+// If no vendor with the name exists, a new Vendor is allocated.
+// The signature must be 12 bytes, as returned in EBX, EDX and ECX of CPUID leaf 0.
+// RegisterVendor is not safe for concurrent use, and Detect must be called
+// for the vendor to be reflected in CPU.
+func RegisterVendor(signature, name string) Vendor {
+	if len(signature) != 12 {
+		panic(fmt.Sprintf("cpuid: vendor signature must be 12 bytes, got %q", signature))
+	}
+	v := Vendor(-1)
+	for i, n := range vendorNames {
+		if n == name {
+			v = Vendor(i)
+			break
+		}
+	}
+	if v < 0 {
+		v = Vendor(len(vendorNames))
+		vendorNames = append(vendorNames, name)
+	}
+	vendorMapping[signature] = v
+	return v
+}
+
 const (
 	CMOV               = 1 << iota // i686 CMOV
 	NX                             // NX (No-Execute) bit
@@ -202,33 +303,146 @@ const (
 	SHA512
 	SVE
 	GPA
+	ASIMDFHM
+	DIT
+	USCAT
+	ILRCPC
+	FLAGM
+	SSBS
+	SB
+	PACA
+	DCPODP
+	SVE2
+	SVEAES
+	SVEPMULL
+	SVEBITPERM
+	SVESHA3
+	SVESM4
+	FLAGM2
+	FRINT
+	SVEI8MM
+	SVEF32MM
+	SVEF64MM
+	SVEBF16
+	I8MM
+	BF16
+	DGH
+	RNG
+	BTI
+	MTE
+	ECV
+	AFP
+	RPRES
+	MTE3
+	WFXT
+	EBF16
+	SVEEBF16
+	CSSC
+	RPRFM
+	MOPS
+	HBC
+	PACG
 )
 
 var flagNamesArm = map[ArmFlags]string{
-	FP:       "FP",       // Single-precision and double-precision floating point
-	ASIMD:    "ASIMD",    // Advanced SIMD
-	EVTSTRM:  "EVTSTRM",  // Generic timer
-	AES:      "AES",      // AES instructions
-	PMULL:    "PMULL",    // Polynomial Multiply instructions (PMULL/PMULL2)
-	SHA1:     "SHA1",     // SHA-1 instructions (SHA1C, etc)
-	SHA2:     "SHA2",     // SHA-2 instructions (SHA256H, etc)
-	CRC32:    "CRC32",    // CRC32/CRC32C instructions
-	ATOMICS:  "ATOMICS",  // Large System Extensions (LSE)
-	FPHP:     "FPHP",     // Half-precision floating point
-	ASIMDHP:  "ASIMDHP",  // Advanced SIMD half-precision floating point
-	ARMCPUID: "CPUID",    // Some CPU ID registers readable at user-level
-	ASIMDRDM: "ASIMDRDM", // Rounding Double Multiply Accumulate/Subtract (SQRDMLAH/SQRDMLSH)
-	JSCVT:    "JSCVT",    // Javascript-style double->int convert (FJCVTZS)
-	FCMA:     "FCMA",     // Floatin point complex number addition and multiplication
-	LRCPC:    "LRCPC",    // Weaker release consistency (LDAPR, etc)
-	DCPOP:    "DCPOP",    // Data cache clean to Point of Persistence (DC CVAP)
-	SHA3:     "SHA3",     // SHA-3 instructions (EOR3, RAXI, XAR, BCAX)
-	SM3:      "SM3",      // SM3 instructions
-	SM4:      "SM4",      // SM4 instructions
-	ASIMDDP:  "ASIMDDP",  // SIMD Dot Product
-	SHA512:   "SHA512",   // SHA512 instructions
-	SVE:      "SVE",      // Scalable Vector Extension
-	GPA:      "GPA",      // Generic Pointer Authentication
+	FP:         "FP",         // Single-precision and double-precision floating point
+	ASIMD:      "ASIMD",      // Advanced SIMD
+	EVTSTRM:    "EVTSTRM",    // Generic timer
+	AES:        "AES",        // AES instructions
+	PMULL:      "PMULL",      // Polynomial Multiply instructions (PMULL/PMULL2)
+	SHA1:       "SHA1",       // SHA-1 instructions (SHA1C, etc)
+	SHA2:       "SHA2",       // SHA-2 instructions (SHA256H, etc)
+	CRC32:      "CRC32",      // CRC32/CRC32C instructions
+	ATOMICS:    "ATOMICS",    // Large System Extensions (LSE)
+	FPHP:       "FPHP",       // Half-precision floating point
+	ASIMDHP:    "ASIMDHP",    // Advanced SIMD half-precision floating point
+	ARMCPUID:   "CPUID",      // Some CPU ID registers readable at user-level
+	ASIMDRDM:   "ASIMDRDM",   // Rounding Double Multiply Accumulate/Subtract (SQRDMLAH/SQRDMLSH)
+	JSCVT:      "JSCVT",      // Javascript-style double->int convert (FJCVTZS)
+	FCMA:       "FCMA",       // Floatin point complex number addition and multiplication
+	LRCPC:      "LRCPC",      // Weaker release consistency (LDAPR, etc)
+	DCPOP:      "DCPOP",      // Data cache clean to Point of Persistence (DC CVAP)
+	SHA3:       "SHA3",       // SHA-3 instructions (EOR3, RAXI, XAR, BCAX)
+	SM3:        "SM3",        // SM3 instructions
+	SM4:        "SM4",        // SM4 instructions
+	ASIMDDP:    "ASIMDDP",    // SIMD Dot Product
+	SHA512:     "SHA512",     // SHA512 instructions
+	SVE:        "SVE",        // Scalable Vector Extension
+	GPA:        "GPA",        // Generic Pointer Authentication using the architected algorithm
+	ASIMDFHM:   "ASIMDFHM",   // SIMD Floating point multiplication and addition (FMLAL/FMLSL)
+	DIT:        "DIT",        // Data Independent Timing
+	USCAT:      "USCAT",      // Unaligned single-copy atomicity and atomic ordering
+	ILRCPC:     "ILRCPC",     // Release consistent processor consistent with immediate offset (LDAPUR, etc)
+	FLAGM:      "FLAGM",      // Condition flag manipulation (CFINV, RMIF, SETF8, SETF16)
+	SSBS:       "SSBS",       // Speculative Store Bypass Safe control (PSTATE.SSBS)
+	SB:         "SB",         // Speculation Barrier
+	PACA:       "PACA",       // Address Pointer Authentication
+	DCPODP:     "DCPODP",     // Data cache clean to Point of Deep Persistence (DC CVADP)
+	SVE2:       "SVE2",       // Scalable Vector Extension 2
+	SVEAES:     "SVEAES",     // SVE AES instructions
+	SVEPMULL:   "SVEPMULL",   // SVE Polynomial Multiply Long on 64-bit elements (PMULLB/PMULLT)
+	SVEBITPERM: "SVEBITPERM", // SVE bit permute instructions (BDEP, BEXT, BGRP)
+	SVESHA3:    "SVESHA3",    // SVE SHA-3 instructions (RAX1)
+	SVESM4:     "SVESM4",     // SVE SM4 instructions
+	FLAGM2:     "FLAGM2",     // Condition flag format conversion (AXFLAG, XAFLAG)
+	FRINT:      "FRINT",      // Round floating point to 32/64-bit integer (FRINT32Z, etc)
+	SVEI8MM:    "SVEI8MM",    // SVE Int8 matrix multiplication
+	SVEF32MM:   "SVEF32MM",   // SVE single-precision floating point matrix multiplication
+	SVEF64MM:   "SVEF64MM",   // SVE double-precision floating point matrix multiplication
+	SVEBF16:    "SVEBF16",    // SVE BFloat16 instructions
+	I8MM:       "I8MM",       // Advanced SIMD Int8 matrix multiplication
+	BF16:       "BF16",       // Advanced SIMD BFloat16 instructions
+	DGH:        "DGH",        // Data Gathering Hint
+	RNG:        "RNG",        // Random number generator (RNDR, RNDRRS)
+	BTI:        "BTI",        // Branch Target Identification
+	MTE:        "MTE",        // Memory Tagging Extension
+	ECV:        "ECV",        // Enhanced Counter Virtualization
+	AFP:        "AFP",        // Alternate floating point behaviour (FPCR.AH, FIZ, NEP)
+	RPRES:      "RPRES",      // Increased precision of reciprocal estimate and square root estimate
+	MTE3:       "MTE3",       // Memory Tagging Extension with asymmetric tag check faults
+	WFXT:       "WFXT",       // WFE and WFI with timeout (WFET, WFIT)
+	EBF16:      "EBF16",      // Extended BFloat16 behaviour (FPCR.EBF)
+	SVEEBF16:   "SVEEBF16",   // SVE extended BFloat16 behaviour
+	CSSC:       "CSSC",       // Common short sequence compression instructions (ABS, CNT, CTZ, SMAX, etc)
+	RPRFM:      "RPRFM",      // Range prefetch memory hint (RPRFM)
+	MOPS:       "MOPS",       // Memory copy and set instructions (CPYP, SETP, etc)
+	HBC:        "HBC",        // Hinted conditional branches (BC.cond)
+	PACG:       "PACG",       // Generic Pointer Authentication using any algorithm
+}
+
+// arm64 Scalable Matrix Extension features, in CPUInfo.SmeFeatures
+const (
+	SME        SmeFlags = 1 << iota // Scalable Matrix Extension
+	SMEI16I64                       // SME 16-bit integer outer products into 64-bit integers
+	SMEF64F64                       // SME double-precision floating point outer products
+	SMEI8I32                        // SME 8-bit integer outer products into 32-bit integers
+	SMEF16F32                       // SME half-precision outer products into single-precision
+	SMEB16F32                       // SME BFloat16 outer products into single-precision
+	SMEF32F32                       // SME single-precision floating point outer products
+	SMEFA64                         // Full A64 instruction set in streaming SVE mode
+	SME2                            // Scalable Matrix Extension 2
+	SME2P1                          // Scalable Matrix Extension 2.1
+	SMEI16I32                       // SME 16-bit integer outer products into 32-bit integers
+	SMEBI32I32                      // SME 1-bit binary outer products into 32-bit integers
+	SMEB16B16                       // SME non-widening BFloat16 instructions
+	SMEF16F16                       // SME non-widening half-precision instructions
+)
+
+var flagNamesSme = map[SmeFlags]string{
+	SME:        "SME",        // Scalable Matrix Extension
+	SMEI16I64:  "SMEI16I64",  // SME 16-bit integer outer products into 64-bit integers
+	SMEF64F64:  "SMEF64F64",  // SME double-precision floating point outer products
+	SMEI8I32:   "SMEI8I32",   // SME 8-bit integer outer products into 32-bit integers
+	SMEF16F32:  "SMEF16F32",  // SME half-precision outer products into single-precision
+	SMEB16F32:  "SMEB16F32",  // SME BFloat16 outer products into single-precision
+	SMEF32F32:  "SMEF32F32",  // SME single-precision floating point outer products
+	SMEFA64:    "SMEFA64",    // Full A64 instruction set in streaming SVE mode
+	SME2:       "SME2",       // Scalable Matrix Extension 2
+	SME2P1:     "SME2P1",     // Scalable Matrix Extension 2.1
+	SMEI16I32:  "SMEI16I32",  // SME 16-bit integer outer products into 32-bit integers
+	SMEBI32I32: "SMEBI32I32", // SME 1-bit binary outer products into 32-bit integers
+	SMEB16B16:  "SMEB16B16",  // SME non-widening BFloat16 instructions
+	SMEF16F16:  "SMEF16F16",  // SME non-widening half-precision instructions
 }
 
 // x86 Advanced Matrix Extensions features, in CPUInfo.AmxFeatures
@@ -244,30 +458,351 @@ var flagNamesAmx = map[AmxFlags]string{
 	AMXINT8: "AMXINT8", // Tile computational operations on 8-bit integers
 }
 
+// x86 instruction set extensions, in CPUInfo.ExtFeatures
+// Flags is full, so later features, like FSRM and CLWB next to ERMS, are added here.
+const (
+	AVXVNNI       ExtFlags = 1 << iota // AVX (VEX encoded) VNNI neural network instructions
+	AVXIFMA                            // AVX (VEX encoded) Integer Fused Multiply-Add
+	AVXVNNIINT8                        // AVX (VEX encoded) VNNI with 8-bit integers
+	AVXVNNIINT16                       // AVX (VEX encoded) VNNI with 16-bit integers
+	AVXNECONVERT                       // AVX (VEX encoded) BF16/FP16 conversion without exceptions
+	AVX512FP16                         // AVX-512 FP16 Instructions
+	CMPCCXADD                          // CMPccXADD instructions
+	FZLRM                              // Fast Zero-Length REP MOVSB
+	FSRS                               // Fast Short REP STOSB
+	FSRCS                              // Fast Short REP CMPSB/SCASB
+	HRESET                             // History reset
+	LAM                                // Linear Address Masking
+	WRMSRNS                            // Non-Serializing Write to Model Specific Register
+	MSRLIST                            // Read/Write List of Model Specific Registers
+	PREFETCHI                          // PREFETCHIT0/1 instruction prefetch
+	SHA512X86                          // SHA-512 instructions
+	SM3X86                             // SM3 hash instructions
+	SM4X86                             // SM4 block cipher instructions
+	RAOINT                             // Remote Atomic Operations on integers
+	AVX10                              // AVX10 converged vector ISA
+	APX                                // Advanced Performance Extensions (APX_F)
+	FSRM                               // Fast Short REP MOVSB
+	CLFLUSHOPT                         // CLFLUSHOPT instruction
+	CLWB                               // Cache Line Write Back
+	CLDEMOTE                           // Cache Line Demote
+	MOVDIRI                            // Move Doubleword as Direct Store
+	MOVDIR64B                          // Move 64 Bytes as Direct Store
+	ENQCMD                             // Enqueue Command
+	PREFETCHWT1                        // PREFETCHWT1 instruction
+	SERIALIZE                          // SERIALIZE instruction
+	TSXLDTRK                           // Intel TSX Suspend Load Address Tracking
+	WAITPKG                            // TPAUSE, UMONITOR and UMWAIT instructions
+	UINTR                              // User Interrupts
+	PCONFIG                            // Platform Configuration
+	PTWRITE                            // PTWRITE instruction
+	KEYLOCKER                          // Intel Key Locker
+	AESKLE                             // AES Key Locker instructions, enabled by the OS
+	WIDEKL                             // AES wide Key Locker instructions, enabled by the OS
+	PADLOCKRNG                         // VIA PadLock random number generator
+	PADLOCKRNGEN                       // VIA PadLock random number generator enabled
+	PADLOCKACE                         // VIA PadLock Advanced Cryptography Engine
+	PADLOCKACEEN                       // VIA PadLock Advanced Cryptography Engine enabled
+	PADLOCKACE2                        // VIA PadLock Advanced Cryptography Engine 2
+	PADLOCKACE2EN                      // VIA PadLock Advanced Cryptography Engine 2 enabled
+	PADLOCKPHE                         // VIA PadLock Hash Engine
+	PADLOCKPHEEN                       // VIA PadLock Hash Engine enabled
+	PADLOCKPMM                         // VIA PadLock Montgomery Multiplier
+	PADLOCKPMMEN                       // VIA PadLock Montgomery Multiplier enabled
+)
+
+var flagNamesExt = map[ExtFlags]string{
+	AVXVNNI:       "AVXVNNI",       // AVX (VEX encoded) VNNI neural network instructions
+	AVXIFMA:       "AVXIFMA",       // AVX (VEX encoded) Integer Fused Multiply-Add
+	AVXVNNIINT8:   "AVXVNNIINT8",   // AVX (VEX encoded) VNNI with 8-bit integers
+	AVXVNNIINT16:  "AVXVNNIINT16",  // AVX (VEX encoded) VNNI with 16-bit integers
+	AVXNECONVERT:  "AVXNECONVERT",  // AVX (VEX encoded) BF16/FP16 conversion without exceptions
+	AVX512FP16:    "AVX512FP16",    // AVX-512 FP16 Instructions
+	CMPCCXADD:     "CMPCCXADD",     // CMPccXADD instructions
+	FZLRM:         "FZLRM",         // Fast Zero-Length REP MOVSB
+	FSRS:          "FSRS",          // Fast Short REP STOSB
+	FSRCS:         "FSRCS",         // Fast Short REP CMPSB/SCASB
+	HRESET:        "HRESET",        // History reset
+	LAM:           "LAM",           // Linear Address Masking
+	WRMSRNS:       "WRMSRNS",       // Non-Serializing Write to Model Specific Register
+	MSRLIST:       "MSRLIST",       // Read/Write List of Model Specific Registers
+	PREFETCHI:     "PREFETCHI",     // PREFETCHIT0/1 instruction prefetch
+	SHA512X86:     "SHA512",        // SHA-512 instructions
+	SM3X86:        "SM3",           // SM3 hash instructions
+	SM4X86:        "SM4",           // SM4 block cipher instructions
+	RAOINT:        "RAOINT",        // Remote Atomic Operations on integers
+	AVX10:         "AVX10",         // AVX10 converged vector ISA
+	APX:           "APX",           // Advanced Performance Extensions (APX_F)
+	FSRM:          "FSRM",          // Fast Short REP MOVSB
+	CLFLUSHOPT:    "CLFLUSHOPT",    // CLFLUSHOPT instruction
+	CLWB:          "CLWB",          // Cache Line Write Back
+	CLDEMOTE:      "CLDEMOTE",      // Cache Line Demote
+	MOVDIRI:       "MOVDIRI",       // Move Doubleword as Direct Store
+	MOVDIR64B:     "MOVDIR64B",     // Move 64 Bytes as Direct Store
+	ENQCMD:        "ENQCMD",        // Enqueue Command
+	PREFETCHWT1:   "PREFETCHWT1",   // PREFETCHWT1 instruction
+	SERIALIZE:     "SERIALIZE",     // SERIALIZE instruction
+	TSXLDTRK:      "TSXLDTRK",      // Intel TSX Suspend Load Address Tracking
+	WAITPKG:       "WAITPKG",       // TPAUSE, UMONITOR and UMWAIT instructions
+	UINTR:         "UINTR",         // User Interrupts
+	PCONFIG:       "PCONFIG",       // Platform Configuration
+	PTWRITE:       "PTWRITE",       // PTWRITE instruction
+	KEYLOCKER:     "KEYLOCKER",     // Intel Key Locker
+	AESKLE:        "AESKLE",        // AES Key Locker instructions, enabled by the OS
+	WIDEKL:        "WIDEKL",        // AES wide Key Locker instructions, enabled by the OS
+	PADLOCKRNG:    "PADLOCKRNG",    // VIA PadLock random number generator
+	PADLOCKRNGEN:  "PADLOCKRNGEN",  // VIA PadLock random number generator enabled
+	PADLOCKACE:    "PADLOCKACE",    // VIA PadLock Advanced Cryptography Engine
+	PADLOCKACEEN:  "PADLOCKACEEN",  // VIA PadLock Advanced Cryptography Engine enabled
+	PADLOCKACE2:   "PADLOCKACE2",   // VIA PadLock Advanced Cryptography Engine 2
+	PADLOCKACE2EN: "PADLOCKACE2EN", // VIA PadLock Advanced Cryptography Engine 2 enabled
+	PADLOCKPHE:    "PADLOCKPHE",    // VIA PadLock Hash Engine
+	PADLOCKPHEEN:  "PADLOCKPHEEN",  // VIA PadLock Hash Engine enabled
+	PADLOCKPMM:    "PADLOCKPMM",    // VIA PadLock Montgomery Multiplier
+	PADLOCKPMMEN:  "PADLOCKPMMEN",  // VIA PadLock Montgomery Multiplier enabled
+}
+
+// x86 system level paging and protection features, in CPUInfo.SysFeatures
+const (
+	PCID     SysFlags = 1 << iota // Process-Context Identifiers
+	INVPCID                       // INVPCID instruction
+	FSGSBASE                      // RDFSBASE/RDGSBASE/WRFSBASE/WRGSBASE instructions
+	SMEP                          // Supervisor-Mode Execution Prevention
+	SMAP                          // Supervisor-Mode Access Prevention
+	UMIP                          // User-Mode Instruction Prevention
+	PKU                           // Protection Keys for User-mode pages
+	OSPKE                         // Protection Keys enabled by the OS, with PKRU state in XCR0
+	LA57                          // 5-level paging (57-bit linear addresses)
+	PDPE1GB                       // 1 GB pages
+	CETSS                         // CET Shadow Stack
+	CETIBT                        // CET Indirect Branch Tracking
+	OSCETSS                       // CET Shadow Stack enabled by the OS for user mode (Linux only)
+	OSCETIBT                      // CET Indirect Branch Tracking enabled for the kernel (Linux only, not usable from user space)
+	TME                           // Total Memory Encryption
+)
+
+var flagNamesSys = map[SysFlags]string{
+	PCID:     "PCID",     // Process-Context Identifiers
+	INVPCID:  "INVPCID",  // INVPCID instruction
+	FSGSBASE: "FSGSBASE", // RDFSBASE/RDGSBASE/WRFSBASE/WRGSBASE instructions
+	SMEP:     "SMEP",     // Supervisor-Mode Execution Prevention
+	SMAP:     "SMAP",     // Supervisor-Mode Access Prevention
+	UMIP:     "UMIP",     // User-Mode Instruction Prevention
+	PKU:      "PKU",      // Protection Keys for User-mode pages
+	OSPKE:    "OSPKE",    // Protection Keys enabled by the OS, with PKRU state in XCR0
+	LA57:     "LA57",     // 5-level paging (57-bit linear addresses)
+	PDPE1GB:  "PDPE1GB",  // 1 GB pages
+	CETSS:    "CETSS",    // CET Shadow Stack
+	CETIBT:   "CETIBT",   // CET Indirect Branch Tracking
+	OSCETSS:  "OSCETSS",  // CET Shadow Stack enabled by the OS for user mode (Linux only)
+	OSCETIBT: "OSCETIBT", // CET Indirect Branch Tracking enabled for the kernel (Linux only, not usable from user space)
+	TME:      "TME",      // Total Memory Encryption
+}
+
+// x86 architectural features from CPUID leaf 1, in CPUInfo.LegacyFeatures
+const (
+	FPU         LegacyFlags = 1 << iota // x87 FPU on chip
+	VME                                 // Virtual-8086 Mode Enhancement
+	DE                                  // Debugging Extensions
+	PSE                                 // Page Size Extensions
+	TSC                                 // Time Stamp Counter
+	MSR                                 // RDMSR and WRMSR instructions
+	PAE                                 // Physical Address Extensions
+	MCE                                 // Machine Check Exception
+	CX8                                 // CMPXCHG8B instruction
+	APIC                                // APIC on chip
+	SEP                                 // SYSENTER and SYSEXIT instructions
+	MTRR                                // Memory Type Range Registers
+	PGE                                 // Page Global Bit
+	MCA                                 // Machine Check Architecture
+	PAT                                 // Page Attribute Table
+	PSE36                               // 36-bit Page Size Extension
+	CLFSH                               // CLFLUSH instruction
+	DS                                  // Debug Store
+	ACPI                                // Thermal Monitor and Software Controlled Clock Facilities
+	FXSR                                // FXSAVE and FXRSTOR instructions
+	SS                                  // Self Snoop
+	TM                                  // Thermal Monitor
+	PBE                                 // Pending Break Enable
+	DTES64                              // 64-bit DS Area
+	MONITOR                             // MONITOR/MWAIT instructions
+	DSCPL                               // CPL Qualified Debug Store
+	SMX                                 // Safer Mode Extensions
+	EST                                 // Enhanced Intel SpeedStep technology
+	TM2                                 // Thermal Monitor 2
+	CNXTID                              // L1 Context ID
+	SDBG                                // Silicon Debug
+	XTPR                                // xTPR Update Control
+	PDCM                                // Perfmon and Debug Capability
+	DCA                                 // Direct Cache Access
+	X2APIC                              // x2APIC
+	MOVBE                               // MOVBE instruction
+	TSCDEADLINE                         // APIC timer TSC deadline mode
+	XSAVE                               // XSAVE/XRSTOR/XSETBV/XGETBV instructions
+	OSXSAVE                             // XSAVE enabled by the OS
+	HYPERVISOR                          // Running under a hypervisor
+)
+
+var flagNamesLegacy = map[LegacyFlags]string{
+	FPU:         "FPU",         // x87 FPU on chip
+	VME:         "VME",         // Virtual-8086 Mode Enhancement
+	DE:          "DE",          // Debugging Extensions
+	PSE:         "PSE",         // Page Size Extensions
+	TSC:         "TSC",         // Time Stamp Counter
+	MSR:         "MSR",         // RDMSR and WRMSR instructions
+	PAE:         "PAE",         // Physical Address Extensions
+	MCE:         "MCE",         // Machine Check Exception
+	CX8:         "CX8",         // CMPXCHG8B instruction
+	APIC:        "APIC",        // APIC on chip
+	SEP:         "SEP",         // SYSENTER and SYSEXIT instructions
+	MTRR:        "MTRR",        // Memory Type Range Registers
+	PGE:         "PGE",         // Page Global Bit
+	MCA:         "MCA",         // Machine Check Architecture
+	PAT:         "PAT",         // Page Attribute Table
+	PSE36:       "PSE36",       // 36-bit Page Size Extension
+	CLFSH:       "CLFSH",       // CLFLUSH instruction
+	DS:          "DS",          // Debug Store
+	ACPI:        "ACPI",        // Thermal Monitor and Software Controlled Clock Facilities
+	FXSR:        "FXSR",        // FXSAVE and FXRSTOR instructions
+	SS:          "SS",          // Self Snoop
+	TM:          "TM",          // Thermal Monitor
+	PBE:         "PBE",         // Pending Break Enable
+	DTES64:      "DTES64",      // 64-bit DS Area
+	MONITOR:     "MONITOR",     // MONITOR/MWAIT instructions
+	DSCPL:       "DSCPL",       // CPL Qualified Debug Store
+	SMX:         "SMX",         // Safer Mode Extensions
+	EST:         "EST",         // Enhanced Intel SpeedStep technology
+	TM2:         "TM2",         // Thermal Monitor 2
+	CNXTID:      "CNXTID",      // L1 Context ID
+	SDBG:        "SDBG",        // Silicon Debug
+	XTPR:        "XTPR",        // xTPR Update Control
+	PDCM:        "PDCM",        // Perfmon and Debug Capability
+	DCA:         "DCA",         // Direct Cache Access
+	X2APIC:      "X2APIC",      // x2APIC
+	MOVBE:       "MOVBE",       // MOVBE instruction
+	TSCDEADLINE: "TSCDEADLINE", // APIC timer TSC deadline mode
+	XSAVE:       "XSAVE",       // XSAVE/XRSTOR/XSETBV/XGETBV instructions
+	OSXSAVE:     "OSXSAVE",     // XSAVE enabled by the OS
+	HYPERVISOR:  "HYPERVISOR",  // Running under a hypervisor
+}
+
+// x86 extended features defined by AMD (CPUID 0x80000001 and 0x80000008), in CPUInfo.AmdFeatures
+const (
+	LAHF           AmdFlags = 1 << iota // LAHF/SAHF in 64-bit mode
+	CMPLEGACY                           // Core multi-processing legacy mode
+	SVM                                 // AMD Secure Virtual Machine
+	EXTAPICSPACE                        // Extended APIC register space
+	ALTMOVCR8                           // LOCK MOV CR0 means MOV CR8
+	ABM                                 // Advanced Bit Manipulation
+	MISALIGNSSE                         // Misaligned SSE mode
+	PREFETCHW                           // PREFETCH and PREFETCHW instructions
+	OSVW                                // OS Visible Workaround
+	IBS                                 // Instruction Based Sampling
+	SKINIT                              // SKINIT and STGI instructions
+	WDT                                 // Watchdog timer
+	LWP                                 // Lightweight profiling
+	TCE                                 // Translation Cache Extension
+	NODEID                              // NodeId MSR
+	TOPOEXT                             // Topology extensions
+	PERFCTREXTCORE                      // Core performance counter extensions
+	PERFCTREXTNB                        // Northbridge performance counter extensions
+	DATABKPTEXT                         // Data breakpoint extension
+	PERFTSC                             // Performance time-stamp counter
+	MONITORX                            // MONITORX and MWAITX instructions
+	CLZERO                              // CLZERO instruction
+	INSTRETCNT                          // Instruction Retired Counter MSR
+	RSTRFPERR                           // FXSAVE/XSAVE always save/restore the error pointers
+	INVLPGB                             // INVLPGB and TLBSYNC instructions
+	RDPRU                               // RDPRU instruction
+	MCOMMIT                             // MCOMMIT instruction
+	WBNOINVD                            // WBNOINVD instruction
+)
+
+var flagNamesAmd = map[AmdFlags]string{
+	LAHF:           "LAHF",           // LAHF/SAHF in 64-bit mode
+	CMPLEGACY:      "CMPLEGACY",      // Core multi-processing legacy mode
+	SVM:            "SVM",            // AMD Secure Virtual Machine
+	EXTAPICSPACE:   "EXTAPICSPACE",   // Extended APIC register space
+	ALTMOVCR8:      "ALTMOVCR8",      // LOCK MOV CR0 means MOV CR8
+	ABM:            "ABM",            // Advanced Bit Manipulation
+	MISALIGNSSE:    "MISALIGNSSE",    // Misaligned SSE mode
+	PREFETCHW:      "PREFETCHW",      // PREFETCH and PREFETCHW instructions
+	OSVW:           "OSVW",           // OS Visible Workaround
+	IBS:            "IBS",            // Instruction Based Sampling
+	SKINIT:         "SKINIT",         // SKINIT and STGI instructions
+	WDT:            "WDT",            // Watchdog timer
+	LWP:            "LWP",            // Lightweight profiling
+	TCE:            "TCE",            // Translation Cache Extension
+	NODEID:         "NODEID",         // NodeId MSR
+	TOPOEXT:        "TOPOEXT",        // Topology extensions
+	PERFCTREXTCORE: "PERFCTREXTCORE", // Core performance counter extensions
+	PERFCTREXTNB:   "PERFCTREXTNB",   // Northbridge performance counter extensions
+	DATABKPTEXT:    "DATABKPTEXT",    // Data breakpoint extension
+	PERFTSC:        "PERFTSC",        // Performance time-stamp counter
+	MONITORX:       "MONITORX",       // MONITORX and MWAITX instructions
+	CLZERO:         "CLZERO",         // CLZERO instruction
+	INSTRETCNT:     "INSTRETCNT",     // Instruction Retired Counter MSR
+	RSTRFPERR:      "RSTRFPERR",      // FXSAVE/XSAVE always save/restore the error pointers
+	INVLPGB:        "INVLPGB",        // INVLPGB and TLBSYNC instructions
+	RDPRU:          "RDPRU",          // RDPRU instruction
+	MCOMMIT:        "MCOMMIT",        // MCOMMIT instruction
+	WBNOINVD:       "WBNOINVD",       // WBNOINVD instruction
+}
+
 // CPUInfo contains information about the detected system CPU.
 type CPUInfo struct {
-	BrandName      string   // Brand name reported by the CPU
-	VendorID       Vendor   // Comparable CPU vendor ID
-	VendorString   string   // Raw vendor string.
-	Features       Flags    // Features of the CPU (x64)
-	Arm            ArmFlags // Features of the CPU (arm)
-	AmxFeatures    AmxFlags // Features of the AMX (x86 Advanced Matrix Extension)
-	PhysicalCores  int      // Number of physical processor cores in your CPU. Will be 0 if undetectable.
-	ThreadsPerCore int      // Number of threads per physical core. Will be 1 if undetectable.
-	LogicalCores   int      // Number of physical cores times threads that can run on each core through the use of hyperthreading. Will be 0 if undetectable.
-	Family         int      // CPU family number
-	Model          int      // CPU model number
-	CacheLine      int      // Cache line size in bytes. Will be 0 if undetectable.
-	Hz             int64    // Clock speed, if known
+	BrandName      string      // Brand name reported by the CPU
+	VendorID       Vendor      // Comparable CPU vendor ID
+	VendorString   string      // Raw vendor string.
+	Features       Flags       // Features of the CPU (x64)
+	Arm            ArmFlags    // Features of the CPU (arm)
+	SmeFeatures    SmeFlags    // Features of the SME (arm64 Scalable Matrix Extension)
+	AmxFeatures    AmxFlags    // Features of the AMX (x86 Advanced Matrix Extension)
+	ExtFeatures    ExtFlags    // x86 instruction set extensions
+	SysFeatures    SysFlags    // x86 system level paging and protection features
+	LegacyFeatures LegacyFlags // x86 architectural features from CPUID leaf 1
+	AmdFeatures    AmdFlags    // x86 extended features defined by AMD
+	PhysicalCores  int         // Number of physical processor cores in your CPU. Will be 0 if undetectable.
+	ThreadsPerCore int         // Number of threads per physical core. Will be 1 if undetectable.
+	LogicalCores   int         // Number of physical cores times threads that can run on each core through the use of hyperthreading. Will be 0 if undetectable.
+	Family         int         // CPU family number
+	Model          int         // CPU model number
+	CacheLine      int         // Cache line size in bytes. Will be 0 if undetectable.
+	Hz             int64       // Clock speed, if known
 	Cache          struct {
 		L1I int // L1 Instruction Cache (per core or shared). Will be -1 if undetected
 		L1D int // L1 Data Cache (per core or shared). Will be -1 if undetected
 		L2  int // L2 Cache (per core or shared). Will be -1 if undetected
 		L3  int // L3 Cache (per core, per ccx or shared). Will be -1 if undetected
+
+		// Associativity of each cache. Will be 0 if undetected.
+		// Fully associative caches report their number of lines.
+		L1IWays int
+		L1DWays int
+		L2Ways  int
+		L3Ways  int
 	}
-	SGX       SGXSupport
+	SGX         SGXSupport
+	KeyLocker   KeyLocker        // Intel Key Locker
+	PMU         PMU              // Performance monitoring capabilities
+	SEV         SEVSupport       // AMD Secure Encrypted Virtualization
+	SVMFeatures SVMSupport       // AMD Secure Virtual Machine capabilities
+	RDT         ResourceDirector // Intel RDT / AMD PQOS resource monitoring and allocation
+	ArmID       ArmID            // arm64 Main ID Register
+	// ArmVectorLength contains the arm64 SVE and SME vector lengths
+	// of the thread that called Detect.
+	ArmVectorLength ArmVectorLength
+
+	// VendorExtras contains vendor specific information.
+	// It is nil if there is nothing vendor specific to report.
+	VendorExtras *VendorExtras
+
 	maxFunc   uint32
 	maxExFunc uint32
+	tdxGuest  bool
+
+	avx10Version int
+	avx10Lengths []int
 }
 
 var cpuid func(op uint32) (eax, ebx, ecx, edx uint32)
@@ -595,273 +1130,1175 @@ func (c CPUInfo) AMXINT8() bool {
 	return c.AmxFeatures&AMXINT8 != 0
 }
 
-// MPX indicates support of Intel MPX (Memory Protection Extensions)
-func (c CPUInfo) MPX() bool {
-	return c.Features&MPX != 0
+// AVXVNNI indicates support of AVX (VEX encoded) VNNI neural network instructions
+func (c CPUInfo) AVXVNNI() bool {
+	return c.ExtFeatures&AVXVNNI != 0
 }
 
-// ERMS indicates support of Enhanced REP MOVSB/STOSB
-func (c CPUInfo) ERMS() bool {
-	return c.Features&ERMS != 0
+// AVXIFMA indicates support of AVX (VEX encoded) Integer Fused Multiply-Add
+func (c CPUInfo) AVXIFMA() bool {
+	return c.ExtFeatures&AVXIFMA != 0
 }
 
-// RDTSCP Instruction is available.
-func (c CPUInfo) RDTSCP() bool {
-	return c.Features&RDTSCP != 0
+// AVXVNNIINT8 indicates support of AVX (VEX encoded) VNNI with 8-bit integers
+func (c CPUInfo) AVXVNNIINT8() bool {
+	return c.ExtFeatures&AVXVNNIINT8 != 0
 }
 
-// CX16 indicates if CMPXCHG16B instruction is available.
-func (c CPUInfo) CX16() bool {
-	return c.Features&CX16 != 0
+// AVXVNNIINT16 indicates support of AVX (VEX encoded) VNNI with 16-bit integers
+func (c CPUInfo) AVXVNNIINT16() bool {
+	return c.ExtFeatures&AVXVNNIINT16 != 0
 }
 
-// TSX is split into HLE (Hardware Lock Elision) and RTM (Restricted Transactional Memory) detection.
-// So TSX simply checks that.
-func (c CPUInfo) TSX() bool {
-	return c.Features&(HLE|RTM) == HLE|RTM
+// AVXNECONVERT indicates support of AVX (VEX encoded) BF16/FP16 conversion without exceptions
+func (c CPUInfo) AVXNECONVERT() bool {
+	return c.ExtFeatures&AVXNECONVERT != 0
 }
 
-// Atom indicates an Atom processor
-func (c CPUInfo) Atom() bool {
-	return c.Features&ATOM != 0
+// AVX512FP16 indicates support of AVX-512 FP16 Instructions
+func (c CPUInfo) AVX512FP16() bool {
+	return c.ExtFeatures&AVX512FP16 != 0
 }
 
-// Intel returns true if vendor is recognized as Intel
-func (c CPUInfo) Intel() bool {
-	return c.VendorID == Intel
+// CMPCCXADD indicates support of CMPccXADD instructions
+func (c CPUInfo) CMPCCXADD() bool {
+	return c.ExtFeatures&CMPCCXADD != 0
 }
 
-// AMD returns true if vendor is recognized as AMD
-func (c CPUInfo) AMD() bool {
-	return c.VendorID == AMD
+// FZLRM indicates support of Fast Zero-Length REP MOVSB
+func (c CPUInfo) FZLRM() bool {
+	return c.ExtFeatures&FZLRM != 0
 }
 
-// Hygon returns true if vendor is recognized as Hygon
-func (c CPUInfo) Hygon() bool {
-	return c.VendorID == Hygon
+// FSRS indicates support of Fast Short REP STOSB
+func (c CPUInfo) FSRS() bool {
+	return c.ExtFeatures&FSRS != 0
 }
 
-// Transmeta returns true if vendor is recognized as Transmeta
-func (c CPUInfo) Transmeta() bool {
-	return c.VendorID == Transmeta
+// FSRCS indicates support of Fast Short REP CMPSB/SCASB
+func (c CPUInfo) FSRCS() bool {
+	return c.ExtFeatures&FSRCS != 0
 }
 
-// NSC returns true if vendor is recognized as National Semiconductor
-func (c CPUInfo) NSC() bool {
-	return c.VendorID == NSC
+// FSRM indicates support of Fast Short REP MOVSB
+func (c CPUInfo) FSRM() bool {
+	return c.ExtFeatures&FSRM != 0
 }
 
-// VIA returns true if vendor is recognized as VIA
-func (c CPUInfo) VIA() bool {
-	return c.VendorID == VIA
+// CLFLUSHOPT indicates support of CLFLUSHOPT instruction
+func (c CPUInfo) CLFLUSHOPT() bool {
+	return c.ExtFeatures&CLFLUSHOPT != 0
 }
 
-// RTCounter returns the 64-bit time-stamp counter
-// Uses the RDTSCP instruction. The value 0 is returned
-// if the CPU does not support the instruction.
-func (c CPUInfo) RTCounter() uint64 {
-	if !c.RDTSCP() {
-		return 0
-	}
-	a, _, _, d := rdtscpAsm()
-	return uint64(a) | (uint64(d) << 32)
+// CLWB indicates support of Cache Line Write Back
+func (c CPUInfo) CLWB() bool {
+	return c.ExtFeatures&CLWB != 0
 }
 
-// Ia32TscAux returns the IA32_TSC_AUX part of the RDTSCP.
-// This variable is OS dependent, but on Linux contains information
-// about the current cpu/core the code is running on.
-// If the RDTSCP instruction isn't supported on the CPU, the value 0 is returned.
-func (c CPUInfo) Ia32TscAux() uint32 {
-	if !c.RDTSCP() {
-		return 0
-	}
-	_, _, ecx, _ := rdtscpAsm()
-	return ecx
+// CLDEMOTE indicates support of Cache Line Demote
+func (c CPUInfo) CLDEMOTE() bool {
+	return c.ExtFeatures&CLDEMOTE != 0
 }
 
-// LogicalCPU will return the Logical CPU the code is currently executing on.
-// This is likely to change when the OS re-schedules the running thread
-// to another CPU.
-// If the current core cannot be detected, -1 will be returned.
-func (c CPUInfo) LogicalCPU() int {
-	if c.maxFunc < 1 {
-		return -1
-	}
-	_, ebx, _, _ := cpuid(1)
-	return int(ebx >> 24)
+// MOVDIRI indicates support of Move Doubleword as Direct Store
+func (c CPUInfo) MOVDIRI() bool {
+	return c.ExtFeatures&MOVDIRI != 0
 }
 
-// hertz tries to compute the clock speed of the CPU. If leaf 15 is
-// supported, use it, otherwise parse the brand string. Yes, really.
-func hertz(model string) int64 {
-	mfi := maxFunctionID()
-	if mfi >= 0x15 {
-		eax, ebx, ecx, _ := cpuid(0x15)
-		if eax != 0 && ebx != 0 && ecx != 0 {
-			return int64((int64(ecx) * int64(ebx)) / int64(eax))
-		}
-	}
-	// computeHz determines the official rated speed of a CPU from its brand
-	// string. This insanity is *actually the official documented way to do
-	// this according to Intel*, prior to leaf 0x15 existing. The official
-	// documentation only shows this working for exactly `x.xx` or `xxxx`
-	// cases, e.g., `2.50GHz` or `1300MHz`; this parser will accept other
-	// sizes.
-	hz := strings.LastIndex(model, "Hz")
-	if hz < 3 {
-		return -1
-	}
-	var multiplier int64
-	switch model[hz-1] {
-	case 'M':
-		multiplier = 1000 * 1000
-	case 'G':
-		multiplier = 1000 * 1000 * 1000
-	case 'T':
-		multiplier = 1000 * 1000 * 1000 * 1000
-	}
-	if multiplier == 0 {
-		return -1
-	}
-	freq := int64(0)
-	divisor := int64(0)
-	decimalShift := int64(1)
-	var i int
-	for i = hz - 2; i >= 0 && model[i] != ' '; i-- {
-		if model[i] >= '0' && model[i] <= '9' {
-			freq += int64(model[i]-'0') * decimalShift
-			decimalShift *= 10
-		} else if model[i] == '.' {
-			if divisor != 0 {
-				return -1
-			}
-			divisor = decimalShift
-		} else {
-			return -1
-		}
-	}
-	// we didn't find a space
-	if i < 0 {
-		return -1
-	}
-	if divisor != 0 {
-		return (freq * multiplier) / divisor
-	}
-	return freq * multiplier
+// MOVDIR64B indicates support of Move 64 Bytes as Direct Store
+func (c CPUInfo) MOVDIR64B() bool {
+	return c.ExtFeatures&MOVDIR64B != 0
 }
 
-// VM Will return true if the cpu id indicates we are in
-// a virtual machine. This is only a hint, and will very likely
-// have many false negatives.
-func (c CPUInfo) VM() bool {
-	switch c.VendorID {
-	case MSVM, KVM, VMware, XenHVM, Bhyve:
-		return true
-	}
-	return false
+// ENQCMD indicates support of Enqueue Command
+func (c CPUInfo) ENQCMD() bool {
+	return c.ExtFeatures&ENQCMD != 0
 }
 
-// Flags contains detected cpu features and characteristics
-type Flags uint64
+// PREFETCHWT1 indicates support of PREFETCHWT1 instruction
+func (c CPUInfo) PREFETCHWT1() bool {
+	return c.ExtFeatures&PREFETCHWT1 != 0
+}
 
-// ArmFlags contains detected ARM cpu features and characteristics
-type ArmFlags uint64
+// SERIALIZE indicates support of SERIALIZE instruction
+func (c CPUInfo) SERIALIZE() bool {
+	return c.ExtFeatures&SERIALIZE != 0
+}
 
-// AmxFlags contains AMX (x86 Advanced Matrix extension) features
-type AmxFlags uint64
+// TSXLDTRK indicates support of Intel TSX Suspend Load Address Tracking
+func (c CPUInfo) TSXLDTRK() bool {
+	return c.ExtFeatures&TSXLDTRK != 0
+}
 
-// String returns a string representation of the detected
-// CPU features.
-func (f Flags) String() string {
-	return strings.Join(f.Strings(), ",")
+// WAITPKG indicates support of TPAUSE, UMONITOR and UMWAIT instructions
+func (c CPUInfo) WAITPKG() bool {
+	return c.ExtFeatures&WAITPKG != 0
 }
 
-// Strings returns an array of the detected features.
-func (f Flags) Strings() []string {
-	r := make([]string, 0, 20)
-	for i := uint(0); i < 64; i++ {
-		key := Flags(1 << i)
-		val := flagNames[key]
-		if f&key != 0 {
-			r = append(r, val)
-		}
-	}
-	return r
+// UINTR indicates support of User Interrupts
+func (c CPUInfo) UINTR() bool {
+	return c.ExtFeatures&UINTR != 0
 }
 
-// String returns a string representation of the detected
-// CPU features.
-func (f ArmFlags) String() string {
-	return strings.Join(f.Strings(), ",")
+// PCONFIG indicates support of Platform Configuration
+func (c CPUInfo) PCONFIG() bool {
+	return c.ExtFeatures&PCONFIG != 0
 }
 
-// Strings returns an array of the detected features.
-func (f ArmFlags) Strings() []string {
-	r := make([]string, 0, 20)
-	for i := uint(0); i < 64; i++ {
-		key := ArmFlags(1 << i)
-		val := flagNamesArm[key]
-		if f&key != 0 {
-			r = append(r, val)
-		}
-	}
-	return r
+// PTWRITE indicates support of PTWRITE instruction
+func (c CPUInfo) PTWRITE() bool {
+	return c.ExtFeatures&PTWRITE != 0
 }
 
-// String returns a string representation of the detected
-// x86 Advanced Matrix Extensions (AMX) features.
-func (f AmxFlags) String() string {
-	return strings.Join(f.Strings(), ",")
+// KEYLOCKER indicates support of Intel Key Locker
+func (c CPUInfo) KEYLOCKER() bool {
+	return c.ExtFeatures&KEYLOCKER != 0
 }
 
-// Strings returns an array of the detected features.
-func (f AmxFlags) Strings() []string {
-	r := make([]string, 0, 20)
-	for i := uint(0); i < 64; i++ {
-		key := AmxFlags(1 << i)
-		val := flagNamesAmx[key]
-		if f&key != 0 {
-			r = append(r, val)
-		}
-	}
-	return r
+// AESKLE indicates support of AES Key Locker instructions, enabled by the OS
+func (c CPUInfo) AESKLE() bool {
+	return c.ExtFeatures&AESKLE != 0
 }
 
-func maxExtendedFunction() uint32 {
-	eax, _, _, _ := cpuid(0x80000000)
-	return eax
+// WIDEKL indicates support of AES wide Key Locker instructions, enabled by the OS
+func (c CPUInfo) WIDEKL() bool {
+	return c.ExtFeatures&WIDEKL != 0
 }
 
-func maxFunctionID() uint32 {
-	a, _, _, _ := cpuid(0)
-	return a
+// PADLOCKRNG indicates support of VIA PadLock random number generator
+func (c CPUInfo) PADLOCKRNG() bool {
+	return c.ExtFeatures&PADLOCKRNG != 0
 }
 
-func brandName() string {
-	if maxExtendedFunction() >= 0x80000004 {
-		v := make([]uint32, 0, 48)
-		for i := uint32(0); i < 3; i++ {
-			a, b, c, d := cpuid(0x80000002 + i)
-			v = append(v, a, b, c, d)
-		}
-		return strings.Trim(string(valAsString(v...)), " ")
-	}
-	return "unknown"
+// PADLOCKRNGEN indicates support of VIA PadLock random number generator enabled
+func (c CPUInfo) PADLOCKRNGEN() bool {
+	return c.ExtFeatures&PADLOCKRNGEN != 0
 }
 
-func threadsPerCore() int {
-	mfi := maxFunctionID()
-	vend, _ := vendorID()
+// PADLOCKACE indicates support of VIA PadLock Advanced Cryptography Engine
+func (c CPUInfo) PADLOCKACE() bool {
+	return c.ExtFeatures&PADLOCKACE != 0
+}
 
-	if mfi < 0x4 || (vend != Intel && vend != AMD) {
-		return 1
-	}
+// PADLOCKACEEN indicates support of VIA PadLock Advanced Cryptography Engine enabled
+func (c CPUInfo) PADLOCKACEEN() bool {
+	return c.ExtFeatures&PADLOCKACEEN != 0
+}
 
-	if mfi < 0xb {
-		if vend != Intel {
-			return 1
-		}
-		_, b, _, d := cpuid(1)
+// PADLOCKACE2 indicates support of VIA PadLock Advanced Cryptography Engine 2
+func (c CPUInfo) PADLOCKACE2() bool {
+	return c.ExtFeatures&PADLOCKACE2 != 0
+}
+
+// PADLOCKACE2EN indicates support of VIA PadLock Advanced Cryptography Engine 2 enabled
+func (c CPUInfo) PADLOCKACE2EN() bool {
+	return c.ExtFeatures&PADLOCKACE2EN != 0
+}
+
+// PADLOCKPHE indicates support of VIA PadLock Hash Engine
+func (c CPUInfo) PADLOCKPHE() bool {
+	return c.ExtFeatures&PADLOCKPHE != 0
+}
+
+// PADLOCKPHEEN indicates support of VIA PadLock Hash Engine enabled
+func (c CPUInfo) PADLOCKPHEEN() bool {
+	return c.ExtFeatures&PADLOCKPHEEN != 0
+}
+
+// PADLOCKPMM indicates support of VIA PadLock Montgomery Multiplier
+func (c CPUInfo) PADLOCKPMM() bool {
+	return c.ExtFeatures&PADLOCKPMM != 0
+}
+
+// PADLOCKPMMEN indicates support of VIA PadLock Montgomery Multiplier enabled
+func (c CPUInfo) PADLOCKPMMEN() bool {
+	return c.ExtFeatures&PADLOCKPMMEN != 0
+}
+
+// HRESET indicates support of History reset
+func (c CPUInfo) HRESET() bool {
+	return c.ExtFeatures&HRESET != 0
+}
+
+// LAM indicates support of Linear Address Masking
+func (c CPUInfo) LAM() bool {
+	return c.ExtFeatures&LAM != 0
+}
+
+// WRMSRNS indicates support of Non-Serializing Write to Model Specific Register
+func (c CPUInfo) WRMSRNS() bool {
+	return c.ExtFeatures&WRMSRNS != 0
+}
+
+// MSRLIST indicates support of Read/Write List of Model Specific Registers
+func (c CPUInfo) MSRLIST() bool {
+	return c.ExtFeatures&MSRLIST != 0
+}
+
+// PREFETCHI indicates support of PREFETCHIT0/1 instruction prefetch
+func (c CPUInfo) PREFETCHI() bool {
+	return c.ExtFeatures&PREFETCHI != 0
+}
+
+// SHA512 indicates support of SHA-512 instructions
+func (c CPUInfo) SHA512() bool {
+	return c.ExtFeatures&SHA512X86 != 0
+}
+
+// SM3 indicates support of SM3 hash instructions
+func (c CPUInfo) SM3() bool {
+	return c.ExtFeatures&SM3X86 != 0
+}
+
+// SM4 indicates support of SM4 block cipher instructions
+func (c CPUInfo) SM4() bool {
+	return c.ExtFeatures&SM4X86 != 0
+}
+
+// RAOINT indicates support of Remote Atomic Operations on integers
+func (c CPUInfo) RAOINT() bool {
+	return c.ExtFeatures&RAOINT != 0
+}
+
+// AVX10 indicates support of AVX10 converged vector ISA
+func (c CPUInfo) AVX10() bool {
+	return c.ExtFeatures&AVX10 != 0
+}
+
+// APX indicates support of Advanced Performance Extensions (APX_F)
+func (c CPUInfo) APX() bool {
+	return c.ExtFeatures&APX != 0
+}
+
+// PCID indicates support of Process-Context Identifiers
+func (c CPUInfo) PCID() bool {
+	return c.SysFeatures&PCID != 0
+}
+
+// INVPCID indicates support of INVPCID instruction
+func (c CPUInfo) INVPCID() bool {
+	return c.SysFeatures&INVPCID != 0
+}
+
+// FSGSBASE indicates support of RDFSBASE/RDGSBASE/WRFSBASE/WRGSBASE instructions
+func (c CPUInfo) FSGSBASE() bool {
+	return c.SysFeatures&FSGSBASE != 0
+}
+
+// SMEP indicates support of Supervisor-Mode Execution Prevention
+func (c CPUInfo) SMEP() bool {
+	return c.SysFeatures&SMEP != 0
+}
+
+// SMAP indicates support of Supervisor-Mode Access Prevention
+func (c CPUInfo) SMAP() bool {
+	return c.SysFeatures&SMAP != 0
+}
+
+// UMIP indicates support of User-Mode Instruction Prevention
+func (c CPUInfo) UMIP() bool {
+	return c.SysFeatures&UMIP != 0
+}
+
+// PKU indicates support of Protection Keys for User-mode pages
+func (c CPUInfo) PKU() bool {
+	return c.SysFeatures&PKU != 0
+}
+
+// OSPKE indicates support of Protection Keys enabled by the OS, with PKRU state in XCR0
+func (c CPUInfo) OSPKE() bool {
+	return c.SysFeatures&OSPKE != 0
+}
+
+// LA57 indicates support of 5-level paging (57-bit linear addresses)
+func (c CPUInfo) LA57() bool {
+	return c.SysFeatures&LA57 != 0
+}
+
+// PDPE1GB indicates support of 1 GB pages
+func (c CPUInfo) PDPE1GB() bool {
+	return c.SysFeatures&PDPE1GB != 0
+}
+
+// CETSS indicates support of CET Shadow Stack
+func (c CPUInfo) CETSS() bool {
+	return c.SysFeatures&CETSS != 0
+}
+
+// CETIBT indicates support of CET Indirect Branch Tracking
+func (c CPUInfo) CETIBT() bool {
+	return c.SysFeatures&CETIBT != 0
+}
+
+// OSCETSS indicates support of CET Shadow Stack enabled by the OS for user mode (Linux only)
+func (c CPUInfo) OSCETSS() bool {
+	return c.SysFeatures&OSCETSS != 0
+}
+
+// OSCETIBT indicates support of CET Indirect Branch Tracking enabled for the kernel (Linux only, not usable from user space)
+func (c CPUInfo) OSCETIBT() bool {
+	return c.SysFeatures&OSCETIBT != 0
+}
+
+// TME indicates support of Total Memory Encryption
+func (c CPUInfo) TME() bool {
+	return c.SysFeatures&TME != 0
+}
+
+// FPU indicates support of x87 FPU on chip
+func (c CPUInfo) FPU() bool {
+	return c.LegacyFeatures&FPU != 0
+}
+
+// VME indicates support of Virtual-8086 Mode Enhancement
+func (c CPUInfo) VME() bool {
+	return c.LegacyFeatures&VME != 0
+}
+
+// DE indicates support of Debugging Extensions
+func (c CPUInfo) DE() bool {
+	return c.LegacyFeatures&DE != 0
+}
+
+// PSE indicates support of Page Size Extensions
+func (c CPUInfo) PSE() bool {
+	return c.LegacyFeatures&PSE != 0
+}
+
+// TSC indicates support of Time Stamp Counter
+func (c CPUInfo) TSC() bool {
+	return c.LegacyFeatures&TSC != 0
+}
+
+// MSR indicates support of RDMSR and WRMSR instructions
+func (c CPUInfo) MSR() bool {
+	return c.LegacyFeatures&MSR != 0
+}
+
+// PAE indicates support of Physical Address Extensions
+func (c CPUInfo) PAE() bool {
+	return c.LegacyFeatures&PAE != 0
+}
+
+// MCE indicates support of Machine Check Exception
+func (c CPUInfo) MCE() bool {
+	return c.LegacyFeatures&MCE != 0
+}
+
+// CX8 indicates support of CMPXCHG8B instruction
+func (c CPUInfo) CX8() bool {
+	return c.LegacyFeatures&CX8 != 0
+}
+
+// APIC indicates support of APIC on chip
+func (c CPUInfo) APIC() bool {
+	return c.LegacyFeatures&APIC != 0
+}
+
+// SEP indicates support of SYSENTER and SYSEXIT instructions
+func (c CPUInfo) SEP() bool {
+	return c.LegacyFeatures&SEP != 0
+}
+
+// MTRR indicates support of Memory Type Range Registers
+func (c CPUInfo) MTRR() bool {
+	return c.LegacyFeatures&MTRR != 0
+}
+
+// PGE indicates support of Page Global Bit
+func (c CPUInfo) PGE() bool {
+	return c.LegacyFeatures&PGE != 0
+}
+
+// MCA indicates support of Machine Check Architecture
+func (c CPUInfo) MCA() bool {
+	return c.LegacyFeatures&MCA != 0
+}
+
+// PAT indicates support of Page Attribute Table
+func (c CPUInfo) PAT() bool {
+	return c.LegacyFeatures&PAT != 0
+}
+
+// PSE36 indicates support of 36-bit Page Size Extension
+func (c CPUInfo) PSE36() bool {
+	return c.LegacyFeatures&PSE36 != 0
+}
+
+// CLFSH indicates support of CLFLUSH instruction
+func (c CPUInfo) CLFSH() bool {
+	return c.LegacyFeatures&CLFSH != 0
+}
+
+// DS indicates support of Debug Store
+func (c CPUInfo) DS() bool {
+	return c.LegacyFeatures&DS != 0
+}
+
+// ACPI indicates support of Thermal Monitor and Software Controlled Clock Facilities
+func (c CPUInfo) ACPI() bool {
+	return c.LegacyFeatures&ACPI != 0
+}
+
+// FXSR indicates support of FXSAVE and FXRSTOR instructions
+func (c CPUInfo) FXSR() bool {
+	return c.LegacyFeatures&FXSR != 0
+}
+
+// SS indicates support of Self Snoop
+func (c CPUInfo) SS() bool {
+	return c.LegacyFeatures&SS != 0
+}
+
+// TM indicates support of Thermal Monitor
+func (c CPUInfo) TM() bool {
+	return c.LegacyFeatures&TM != 0
+}
+
+// PBE indicates support of Pending Break Enable
+func (c CPUInfo) PBE() bool {
+	return c.LegacyFeatures&PBE != 0
+}
+
+// DTES64 indicates support of 64-bit DS Area
+func (c CPUInfo) DTES64() bool {
+	return c.LegacyFeatures&DTES64 != 0
+}
+
+// MONITOR indicates support of MONITOR/MWAIT instructions
+func (c CPUInfo) MONITOR() bool {
+	return c.LegacyFeatures&MONITOR != 0
+}
+
+// DSCPL indicates support of CPL Qualified Debug Store
+func (c CPUInfo) DSCPL() bool {
+	return c.LegacyFeatures&DSCPL != 0
+}
+
+// SMX indicates support of Safer Mode Extensions
+func (c CPUInfo) SMX() bool {
+	return c.LegacyFeatures&SMX != 0
+}
+
+// EST indicates support of Enhanced Intel SpeedStep technology
+func (c CPUInfo) EST() bool {
+	return c.LegacyFeatures&EST != 0
+}
+
+// TM2 indicates support of Thermal Monitor 2
+func (c CPUInfo) TM2() bool {
+	return c.LegacyFeatures&TM2 != 0
+}
+
+// CNXTID indicates support of L1 Context ID
+func (c CPUInfo) CNXTID() bool {
+	return c.LegacyFeatures&CNXTID != 0
+}
+
+// SDBG indicates support of Silicon Debug
+func (c CPUInfo) SDBG() bool {
+	return c.LegacyFeatures&SDBG != 0
+}
+
+// XTPR indicates support of xTPR Update Control
+func (c CPUInfo) XTPR() bool {
+	return c.LegacyFeatures&XTPR != 0
+}
+
+// PDCM indicates support of Perfmon and Debug Capability
+func (c CPUInfo) PDCM() bool {
+	return c.LegacyFeatures&PDCM != 0
+}
+
+// DCA indicates support of Direct Cache Access
+func (c CPUInfo) DCA() bool {
+	return c.LegacyFeatures&DCA != 0
+}
+
+// X2APIC indicates support of x2APIC
+func (c CPUInfo) X2APIC() bool {
+	return c.LegacyFeatures&X2APIC != 0
+}
+
+// MOVBE indicates support of MOVBE instruction
+func (c CPUInfo) MOVBE() bool {
+	return c.LegacyFeatures&MOVBE != 0
+}
+
+// TSCDEADLINE indicates support of APIC timer TSC deadline mode
+func (c CPUInfo) TSCDEADLINE() bool {
+	return c.LegacyFeatures&TSCDEADLINE != 0
+}
+
+// XSAVE indicates support of XSAVE/XRSTOR/XSETBV/XGETBV instructions
+func (c CPUInfo) XSAVE() bool {
+	return c.LegacyFeatures&XSAVE != 0
+}
+
+// OSXSAVE indicates support of XSAVE enabled by the OS
+func (c CPUInfo) OSXSAVE() bool {
+	return c.LegacyFeatures&OSXSAVE != 0
+}
+
+// HYPERVISOR indicates support of Running under a hypervisor
+func (c CPUInfo) HYPERVISOR() bool {
+	return c.LegacyFeatures&HYPERVISOR != 0
+}
+
+// LAHF indicates support of LAHF/SAHF in 64-bit mode
+func (c CPUInfo) LAHF() bool {
+	return c.AmdFeatures&LAHF != 0
+}
+
+// CMPLEGACY indicates support of Core multi-processing legacy mode
+func (c CPUInfo) CMPLEGACY() bool {
+	return c.AmdFeatures&CMPLEGACY != 0
+}
+
+// SVM indicates support of AMD Secure Virtual Machine
+func (c CPUInfo) SVM() bool {
+	return c.AmdFeatures&SVM != 0
+}
+
+// EXTAPICSPACE indicates support of Extended APIC register space
+func (c CPUInfo) EXTAPICSPACE() bool {
+	return c.AmdFeatures&EXTAPICSPACE != 0
+}
+
+// ALTMOVCR8 indicates support of LOCK MOV CR0 means MOV CR8
+func (c CPUInfo) ALTMOVCR8() bool {
+	return c.AmdFeatures&ALTMOVCR8 != 0
+}
+
+// ABM indicates support of Advanced Bit Manipulation
+func (c CPUInfo) ABM() bool {
+	return c.AmdFeatures&ABM != 0
+}
+
+// MISALIGNSSE indicates support of Misaligned SSE mode
+func (c CPUInfo) MISALIGNSSE() bool {
+	return c.AmdFeatures&MISALIGNSSE != 0
+}
+
+// PREFETCHW indicates support of PREFETCH and PREFETCHW instructions
+func (c CPUInfo) PREFETCHW() bool {
+	return c.AmdFeatures&PREFETCHW != 0
+}
+
+// OSVW indicates support of OS Visible Workaround
+func (c CPUInfo) OSVW() bool {
+	return c.AmdFeatures&OSVW != 0
+}
+
+// IBS indicates support of Instruction Based Sampling
+func (c CPUInfo) IBS() bool {
+	return c.AmdFeatures&IBS != 0
+}
+
+// SKINIT indicates support of SKINIT and STGI instructions
+func (c CPUInfo) SKINIT() bool {
+	return c.AmdFeatures&SKINIT != 0
+}
+
+// WDT indicates support of Watchdog timer
+func (c CPUInfo) WDT() bool {
+	return c.AmdFeatures&WDT != 0
+}
+
+// LWP indicates support of Lightweight profiling
+func (c CPUInfo) LWP() bool {
+	return c.AmdFeatures&LWP != 0
+}
+
+// TCE indicates support of Translation Cache Extension
+func (c CPUInfo) TCE() bool {
+	return c.AmdFeatures&TCE != 0
+}
+
+// NODEID indicates support of NodeId MSR
+func (c CPUInfo) NODEID() bool {
+	return c.AmdFeatures&NODEID != 0
+}
+
+// TOPOEXT indicates support of Topology extensions
+func (c CPUInfo) TOPOEXT() bool {
+	return c.AmdFeatures&TOPOEXT != 0
+}
+
+// PERFCTREXTCORE indicates support of Core performance counter extensions
+func (c CPUInfo) PERFCTREXTCORE() bool {
+	return c.AmdFeatures&PERFCTREXTCORE != 0
+}
+
+// PERFCTREXTNB indicates support of Northbridge performance counter extensions
+func (c CPUInfo) PERFCTREXTNB() bool {
+	return c.AmdFeatures&PERFCTREXTNB != 0
+}
+
+// DATABKPTEXT indicates support of Data breakpoint extension
+func (c CPUInfo) DATABKPTEXT() bool {
+	return c.AmdFeatures&DATABKPTEXT != 0
+}
+
+// PERFTSC indicates support of Performance time-stamp counter
+func (c CPUInfo) PERFTSC() bool {
+	return c.AmdFeatures&PERFTSC != 0
+}
+
+// MONITORX indicates support of MONITORX and MWAITX instructions
+func (c CPUInfo) MONITORX() bool {
+	return c.AmdFeatures&MONITORX != 0
+}
+
+// CLZERO indicates support of CLZERO instruction
+func (c CPUInfo) CLZERO() bool {
+	return c.AmdFeatures&CLZERO != 0
+}
+
+// INSTRETCNT indicates support of Instruction Retired Counter MSR
+func (c CPUInfo) INSTRETCNT() bool {
+	return c.AmdFeatures&INSTRETCNT != 0
+}
+
+// RSTRFPERR indicates support of FXSAVE/XSAVE always save/restore the error pointers
+func (c CPUInfo) RSTRFPERR() bool {
+	return c.AmdFeatures&RSTRFPERR != 0
+}
+
+// INVLPGB indicates support of INVLPGB and TLBSYNC instructions
+func (c CPUInfo) INVLPGB() bool {
+	return c.AmdFeatures&INVLPGB != 0
+}
+
+// RDPRU indicates support of RDPRU instruction
+func (c CPUInfo) RDPRU() bool {
+	return c.AmdFeatures&RDPRU != 0
+}
+
+// MCOMMIT indicates support of MCOMMIT instruction
+func (c CPUInfo) MCOMMIT() bool {
+	return c.AmdFeatures&MCOMMIT != 0
+}
+
+// WBNOINVD indicates support of WBNOINVD instruction
+func (c CPUInfo) WBNOINVD() bool {
+	return c.AmdFeatures&WBNOINVD != 0
+}
+
+// AVX10Version returns the supported AVX10 version,
+// or 0 if AVX10 is not supported or not enabled by the OS.
+func (c CPUInfo) AVX10Version() int {
+	return c.avx10Version
+}
+
+// AVX10VectorLengths returns the vector lengths in bits supported by AVX10
+// and enabled by the OS, in increasing order.
+// For example AVX10/256 will return [128 256].
+func (c CPUInfo) AVX10VectorLengths() []int {
+	return c.avx10Lengths
+}
+
+// MPX indicates support of Intel MPX (Memory Protection Extensions)
+func (c CPUInfo) MPX() bool {
+	return c.Features&MPX != 0
+}
+
+// ERMS indicates support of Enhanced REP MOVSB/STOSB
+func (c CPUInfo) ERMS() bool {
+	return c.Features&ERMS != 0
+}
+
+// RDTSCP Instruction is available.
+func (c CPUInfo) RDTSCP() bool {
+	return c.Features&RDTSCP != 0
+}
+
+// CX16 indicates if CMPXCHG16B instruction is available.
+func (c CPUInfo) CX16() bool {
+	return c.Features&CX16 != 0
+}
+
+// TSX is split into HLE (Hardware Lock Elision) and RTM (Restricted Transactional Memory) detection.
+// So TSX simply checks that.
+func (c CPUInfo) TSX() bool {
+	return c.Features&(HLE|RTM) == HLE|RTM
+}
+
+// Atom indicates an Atom processor
+func (c CPUInfo) Atom() bool {
+	return c.Features&ATOM != 0
+}
+
+// Intel returns true if vendor is recognized as Intel
+func (c CPUInfo) Intel() bool {
+	return c.VendorID == Intel
+}
+
+// AMD returns true if vendor is recognized as AMD
+func (c CPUInfo) AMD() bool {
+	return c.VendorID == AMD
+}
+
+// Hygon returns true if vendor is recognized as Hygon
+func (c CPUInfo) Hygon() bool {
+	return c.VendorID == Hygon
+}
+
+// Transmeta returns true if vendor is recognized as Transmeta
+func (c CPUInfo) Transmeta() bool {
+	return c.VendorID == Transmeta
+}
+
+// NSC returns true if vendor is recognized as National Semiconductor
+func (c CPUInfo) NSC() bool {
+	return c.VendorID == NSC
+}
+
+// VIA returns true if vendor is recognized as VIA
+func (c CPUInfo) VIA() bool {
+	return c.VendorID == VIA
+}
+
+// Cyrix returns true if vendor is recognized as Cyrix
+func (c CPUInfo) Cyrix() bool {
+	return c.VendorID == Cyrix
+}
+
+// RTCounter returns the 64-bit time-stamp counter
+// Uses the RDTSCP instruction. The value 0 is returned
+// if the CPU does not support the instruction.
+func (c CPUInfo) RTCounter() uint64 {
+	if !c.RDTSCP() {
+		return 0
+	}
+	a, _, _, d := rdtscpAsm()
+	return uint64(a) | (uint64(d) << 32)
+}
+
+// Ia32TscAux returns the IA32_TSC_AUX part of the RDTSCP.
+// This variable is OS dependent, but on Linux contains information
+// about the current cpu/core the code is running on.
+// If the RDTSCP instruction isn't supported on the CPU, the value 0 is returned.
+func (c CPUInfo) Ia32TscAux() uint32 {
+	if !c.RDTSCP() {
+		return 0
+	}
+	_, _, ecx, _ := rdtscpAsm()
+	return ecx
+}
+
+// LogicalCPU will return the Logical CPU the code is currently executing on.
+// This is likely to change when the OS re-schedules the running thread
+// to another CPU.
+// If the current core cannot be detected, -1 will be returned.
+func (c CPUInfo) LogicalCPU() int {
+	if c.maxFunc < 1 {
+		return -1
+	}
+	_, ebx, _, _ := cpuid(1)
+	return int(ebx >> 24)
+}
+
+// hertz tries to compute the clock speed of the CPU. If leaf 15 is
+// supported, use it, otherwise parse the brand string. Yes, really.
+func hertz(model string) int64 {
+	mfi := maxFunctionID()
+	if mfi >= 0x15 {
+		eax, ebx, ecx, _ := cpuid(0x15)
+		if eax != 0 && ebx != 0 && ecx != 0 {
+			return int64((int64(ecx) * int64(ebx)) / int64(eax))
+		}
+	}
+	// computeHz determines the official rated speed of a CPU from its brand
+	// string. This insanity is *actually the official documented way to do
+	// this according to Intel*, prior to leaf 0x15 existing. The official
+	// documentation only shows this working for exactly `x.xx` or `xxxx`
+	// cases, e.g., `2.50GHz` or `1300MHz`; this parser will accept other
+	// sizes.
+	hz := strings.LastIndex(model, "Hz")
+	if hz < 3 {
+		return -1
+	}
+	var multiplier int64
+	switch model[hz-1] {
+	case 'M':
+		multiplier = 1000 * 1000
+	case 'G':
+		multiplier = 1000 * 1000 * 1000
+	case 'T':
+		multiplier = 1000 * 1000 * 1000 * 1000
+	}
+	if multiplier == 0 {
+		return -1
+	}
+	freq := int64(0)
+	divisor := int64(0)
+	decimalShift := int64(1)
+	var i int
+	for i = hz - 2; i >= 0 && model[i] != ' '; i-- {
+		if model[i] >= '0' && model[i] <= '9' {
+			freq += int64(model[i]-'0') * decimalShift
+			decimalShift *= 10
+		} else if model[i] == '.' {
+			if divisor != 0 {
+				return -1
+			}
+			divisor = decimalShift
+		} else {
+			return -1
+		}
+	}
+	// we didn't find a space
+	if i < 0 {
+		return -1
+	}
+	if divisor != 0 {
+		return (freq * multiplier) / divisor
+	}
+	return freq * multiplier
+}
+
+// VM Will return true if the cpu id indicates we are in
+// a virtual machine. This is only a hint, and will very likely
+// have many false negatives.
+func (c CPUInfo) VM() bool {
+	switch c.VendorID {
+	case MSVM, KVM, VMware, XenHVM, Bhyve, QEMU, ACRN, Parallels, QNX:
+		return true
+	}
+	return false
+}
+
+// VirtualizationInfo summarizes hardware virtualization support.
+// The KVM fields are only detected on Linux.
+type VirtualizationInfo struct {
+	VMX        bool // Intel VT-x
+	SVM        bool // AMD-V
+	NPT        bool // AMD nested page tables, as reported by CPUID
+	Hypervisor bool // Running under a hypervisor, which must expose VMX or SVM to allow nesting
+
+	KVM               bool   // /dev/kvm is present
+	KVMModule         string // The loaded KVM vendor module, "kvm_intel" or "kvm_amd"
+	Nested            bool   // KVM allows nested virtualization
+	EPT               bool   // KVM uses EPT (kvm_intel) or NPT (kvm_amd)
+	UnrestrictedGuest bool   // KVM allows unrestricted guests (kvm_intel)
+}
+
+// CanHostVMs returns whether hardware accelerated virtual machines can be started.
+func (v VirtualizationInfo) CanHostVMs() bool {
+	return (v.VMX || v.SVM) && v.KVM
+}
+
+// Virtualization returns a summary of hardware virtualization support.
+// On Linux the KVM state is read from /dev/kvm and /sys/module when called.
+func (c CPUInfo) Virtualization() VirtualizationInfo {
+	return c.VirtualizationRoots(sysfsRoot, devRoot)
+}
+
+// VirtualizationRoots is like Virtualization, but reads the KVM module
+// parameters below the given sysfs root and the KVM device below the given dev root.
+func (c CPUInfo) VirtualizationRoots(sysfs, dev string) VirtualizationInfo {
+	v := VirtualizationInfo{
+		VMX:        c.VMX(),
+		SVM:        c.SVM(),
+		NPT:        c.SVMFeatures.NPT,
+		Hypervisor: c.LegacyFeatures&HYPERVISOR != 0,
+		KVM:        osFileExists(dev, "kvm"),
+	}
+	param := func(name string) bool {
+		val, ok := osReadString(sysfs, filepath.Join("module", v.KVMModule, "parameters", name))
+		return ok && (val == "Y" || val == "1")
+	}
+	switch {
+	case v.VMX && osFileExists(sysfs, "module/kvm_intel"):
+		v.KVMModule = "kvm_intel"
+		v.Nested = param("nested")
+		v.EPT = param("ept")
+		v.UnrestrictedGuest = param("unrestricted_guest")
+	case v.SVM && osFileExists(sysfs, "module/kvm_amd"):
+		v.KVMModule = "kvm_amd"
+		v.Nested = param("nested")
+		v.EPT = param("npt")
+	}
+	return v
+}
+
+// TDXGuest returns true if we are running inside an Intel TDX trust domain.
+func (c CPUInfo) TDXGuest() bool {
+	return c.tdxGuest
+}
+
+// CCTechnology is a confidential computing technology.
+type CCTechnology int
+
+const (
+	CCNone   CCTechnology = iota // No confidential computing technology detected
+	CCSEV                        // AMD Secure Encrypted Virtualization
+	CCSEVES                      // AMD SEV Encrypted State
+	CCSEVSNP                     // AMD SEV Secure Nested Paging
+	CCTDX                        // Intel Trust Domain Extensions
+)
+
+var ccNames = map[CCTechnology]string{
+	CCNone:   "None",
+	CCSEV:    "SEV",
+	CCSEVES:  "SEV-ES",
+	CCSEVSNP: "SEV-SNP",
+	CCTDX:    "TDX",
+}
+
+// String returns the name of the technology.
+func (t CCTechnology) String() string {
+	return ccNames[t]
+}
+
+// CCReport describes the confidential computing state.
+type CCReport struct {
+	// Technology is the strongest technology detected.
+	// When Active is set this is the technology protecting the running guest,
+	// otherwise it is the best technology supported by the CPU.
+	Technology CCTechnology
+	// Active indicates that we are running in a guest protected by Technology.
+	Active bool
+}
+
+// ConfidentialComputing returns the confidential computing state
+// of the environment we are running in.
+// SEV guest state is only detected on Linux.
+// Active is a best-effort hint and not an attestation of the guest.
+func (c CPUInfo) ConfidentialComputing() CCReport {
+	if c.tdxGuest {
+		return CCReport{Technology: CCTDX, Active: true}
+	}
+	s := c.SEV
+	switch {
+	case s.GuestSEVSNP:
+		return CCReport{Technology: CCSEVSNP, Active: true}
+	case s.GuestSEVES:
+		return CCReport{Technology: CCSEVES, Active: true}
+	case s.GuestSEV:
+		return CCReport{Technology: CCSEV, Active: true}
+	case s.SEVSNP:
+		return CCReport{Technology: CCSEVSNP}
+	case s.SEVES:
+		return CCReport{Technology: CCSEVES}
+	case s.SEV:
+		return CCReport{Technology: CCSEV}
+	}
+	return CCReport{}
+}
+
+// Flags contains detected cpu features and characteristics
+type Flags uint64
+
+// ArmFlags contains detected ARM cpu features and characteristics
+type ArmFlags uint64
+
+// AmxFlags contains AMX (x86 Advanced Matrix extension) features
+type AmxFlags uint64
+
+// SmeFlags contains SME (arm64 Scalable Matrix Extension) features
+type SmeFlags uint64
+
+// ExtFlags contains x86 instruction set extension features
+type ExtFlags uint64
+
+// SysFlags contains x86 system level paging and protection features
+type SysFlags uint64
+
+// LegacyFlags contains x86 architectural features from CPUID leaf 1
+type LegacyFlags uint64
+
+// AmdFlags contains x86 extended features defined by AMD
+type AmdFlags uint64
+
+// String returns a string representation of the detected
+// CPU features.
+func (f Flags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f Flags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := Flags(1 << i)
+		val := flagNames[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// CPU features.
+func (f ArmFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f ArmFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := ArmFlags(1 << i)
+		val := flagNamesArm[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// x86 Advanced Matrix Extensions (AMX) features.
+func (f AmxFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f AmxFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := AmxFlags(1 << i)
+		val := flagNamesAmx[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// CPU features.
+func (f SmeFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f SmeFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := SmeFlags(1 << i)
+		val := flagNamesSme[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// x86 instruction set extensions.
+func (f ExtFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f ExtFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := ExtFlags(1 << i)
+		val := flagNamesExt[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// x86 system level features.
+func (f SysFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f SysFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := SysFlags(1 << i)
+		val := flagNamesSys[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// x86 architectural features.
+func (f LegacyFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f LegacyFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := LegacyFlags(1 << i)
+		val := flagNamesLegacy[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+// String returns a string representation of the detected
+// AMD extended features.
+func (f AmdFlags) String() string {
+	return strings.Join(f.Strings(), ",")
+}
+
+// Strings returns an array of the detected features.
+func (f AmdFlags) Strings() []string {
+	r := make([]string, 0, 20)
+	for i := uint(0); i < 64; i++ {
+		key := AmdFlags(1 << i)
+		val := flagNamesAmd[key]
+		if f&key != 0 {
+			r = append(r, val)
+		}
+	}
+	return r
+}
+
+func maxExtendedFunction() uint32 {
+	eax, _, _, _ := cpuid(0x80000000)
+	return eax
+}
+
+func maxFunctionID() uint32 {
+	a, _, _, _ := cpuid(0)
+	return a
+}
+
+func brandName() string {
+	if maxExtendedFunction() >= 0x80000004 {
+		v := make([]uint32, 0, 48)
+		for i := uint32(0); i < 3; i++ {
+			a, b, c, d := cpuid(0x80000002 + i)
+			v = append(v, a, b, c, d)
+		}
+		return strings.Trim(string(valAsString(v...)), " ")
+	}
+	return "unknown"
+}
+
+func threadsPerCore() int {
+	mfi := maxFunctionID()
+	caps := vendorCaps()
+
+	if mfi < 0x4 || (!caps.intelLeaves && !caps.amdCompatible) {
+		return 1
+	}
+
+	if mfi < 0xb {
+		if caps.amdCompatible {
+			return amdThreadsPerCore()
+		}
+		_, b, _, d := cpuid(1)
 		if (d & (1 << 28)) != 0 {
 			// v will contain logical core count
 			v := (b >> 16) & 255
@@ -878,21 +2315,43 @@ func threadsPerCore() int {
 	}
 	_, b, _, _ := cpuidex(0xb, 0)
 	if b&0xffff == 0 {
+		if caps.amdCompatible {
+			return amdThreadsPerCore()
+		}
 		return 1
 	}
 	return int(b & 0xffff)
 }
 
+// amdThreadsPerCore returns the number of threads per core from the AMD topology extensions.
+func amdThreadsPerCore() int {
+	if maxExtendedFunction() < 0x8000001e {
+		return 1
+	}
+	_, _, c, _ := cpuid(0x80000001)
+	// Before family 17h the field holds the number of cores per compute unit.
+	if family, _ := familyModel(); c&(1<<22) == 0 || family < 0x17 {
+		return 1
+	}
+	_, b, _, _ := cpuid(0x8000001e)
+	return int((b>>8)&0xff) + 1
+}
+
 func logicalCores() int {
 	mfi := maxFunctionID()
-	v, _ := vendorID()
-	switch v {
-	case Intel:
+	caps := vendorCaps()
+	switch {
+	case caps.intelLeaves:
 		// Use this on old Intel processors
 		if mfi < 0xb {
 			if mfi < 1 {
 				return 0
 			}
+			if caps.centaurLeaves {
+				// VIA and Zhaoxin leave gaps in the APIC IDs, so leaf 1 and 4 only give an upper bound.
+				logical, _ := osCoreCount()
+				return logical
+			}
 			// CPUID.1:EBX[23:16] represents the maximum number of addressable IDs (initial APIC ID)
 			// that can be assigned to logical processors in a physical package.
 			// The value may not be the same as the number of logical processors that are present in the hardware of a physical package.
@@ -902,7 +2361,7 @@ func logicalCores() int {
 		}
 		_, b, _, _ := cpuidex(0xb, 1)
 		return int(b & 0xffff)
-	case AMD, Hygon:
+	case caps.amdCompatible:
 		_, b, _, _ := cpuid(1)
 		return int((b >> 16) & 0xff)
 	default:
@@ -921,11 +2380,14 @@ func familyModel() (int, int) {
 }
 
 func physicalCores() int {
-	v, _ := vendorID()
-	switch v {
-	case Intel:
+	caps := vendorCaps()
+	switch {
+	case caps.centaurLeaves && maxFunctionID() < 0xb:
+		_, physical := osCoreCount()
+		return physical
+	case caps.intelLeaves:
 		return logicalCores() / threadsPerCore()
-	case AMD, Hygon:
+	case caps.amdCompatible:
 		lc := logicalCores()
 		tpc := threadsPerCore()
 		if lc > 0 && tpc > 0 {
@@ -961,6 +2423,133 @@ var vendorMapping = map[string]Vendor{
 	"SiS SiS SiS ": SiS,
 	"RiseRiseRise": SiS,
 	"Genuine  RDC": RDC,
+	"  Shanghai  ": Zhaoxin,
+	"CyrixInstead": Cyrix,
+	"TCGTCGTCGTCG": QEMU,
+	"ACRNACRNACRN": ACRN,
+	" lrpepyh  vr": Parallels,
+	" QNXQVMBSQG ": QNX,
+	"VirtualApple": AppleRosetta,
+	"MicrosoftXTA": MicrosoftXTA,
+}
+
+// VendorExtras contains vendor specific information for legacy and embedded vendors.
+type VendorExtras struct {
+	// ModelName is the name of processors without a brand string,
+	// derived from the vendor string, family and model.
+	ModelName string
+
+	// Transmeta contains information from the Transmeta leaves (0x80860000).
+	// Only set on Transmeta processors.
+	Transmeta *TransmetaInfo
+}
+
+// TransmetaInfo contains information about Transmeta Crusoe and Efficeon processors.
+type TransmetaInfo struct {
+	HardwareRevision string // Processor hardware revision
+	CMSRevision      string // Code Morphing Software revision
+	NominalMHz       int    // Nominal frequency in MHz
+	LongRun          bool   // LongRun power management
+	Info             string // Code Morphing Software information string
+}
+
+type legacyModel struct {
+	vendor        string
+	family, model int
+}
+
+// legacyModelNames contains names of processors that do not report a brand string.
+// The vendor string is used, since several of these vendors share a Vendor.
+var legacyModelNames = map[legacyModel]string{
+	{"CyrixInstead", 5, 2}: "Cyrix 6x86",
+	{"CyrixInstead", 5, 3}: "Cyrix 6x86",
+	{"CyrixInstead", 5, 4}: "Cyrix MediaGX",
+	{"CyrixInstead", 6, 0}: "Cyrix 6x86MX/MII",
+	{"Geode by NSC", 5, 4}: "NSC Geode GX1",
+	{"Geode by NSC", 5, 5}: "NSC Geode GX2",
+	{"SiS SiS SiS ", 5, 0}: "SiS 55x",
+	{"RiseRiseRise", 5, 0}: "Rise mP6",
+	{"RiseRiseRise", 5, 2}: "Rise mP6",
+	{"RiseRiseRise", 5, 8}: "Rise mP6 II",
+	{"RiseRiseRise", 5, 9}: "Rise mP6 II",
+	{"Vortex86 SoC", 5, 2}: "Vortex86DX",
+	{"Vortex86 SoC", 5, 8}: "Vortex86MX",
+	{"Vortex86 SoC", 6, 0}: "Vortex86EX",
+	{"Genuine  RDC", 5, 8}: "RDC SoC",
+}
+
+// vendorExtras fills VendorExtras.
+// If the processor has no brand string, the model name is used as BrandName.
+// The Transmeta nominal frequency is used if Hz is unknown.
+func (c *CPUInfo) vendorExtras() {
+	c.VendorExtras = nil
+	var extras VendorExtras
+	extras.ModelName = legacyModelNames[legacyModel{vendor: c.VendorString, family: c.Family, model: c.Model}]
+	if c.VendorID == Transmeta {
+		extras.Transmeta = transmeta()
+	}
+	if extras.ModelName == "" && extras.Transmeta == nil {
+		return
+	}
+	c.VendorExtras = &extras
+	if c.BrandName == "unknown" && extras.ModelName != "" {
+		c.BrandName = extras.ModelName
+	}
+	if c.Hz <= 0 && extras.Transmeta != nil {
+		c.Hz = int64(extras.Transmeta.NominalMHz) * 1000000
+	}
+}
+
+// transmeta returns information from the Transmeta leaves.
+func transmeta() *TransmetaInfo {
+	mx, _, _, _ := cpuid(0x80860000)
+	if mx < 0x80860001 || mx > 0x8086ffff {
+		return nil
+	}
+	var rval TransmetaInfo
+	_, rev, freq, flags := cpuid(0x80860001)
+	rval.NominalMHz = int(freq)
+	rval.LongRun = flags&(1<<1) != 0
+	rval.HardwareRevision = fmt.Sprintf("%d.%d.%d.%d", rev>>24, (rev>>16)&0xff, (rev>>8)&0xff, rev&0xff)
+	if mx >= 0x80860002 {
+		newRev, cms1, cms2, _ := cpuid(0x80860002)
+		// Later processors report the hardware revision in leaf 0x80860002.
+		if rev == 0x02000000 {
+			rval.HardwareRevision = fmt.Sprintf("%08X", newRev)
+		}
+		rval.CMSRevision = fmt.Sprintf("%d.%d.%d-%d-%d", cms1>>24, (cms1>>16)&0xff, (cms1>>8)&0xff, cms1&0xff, cms2)
+	}
+	if mx >= 0x80860006 {
+		v := make([]uint32, 0, 16)
+		for i := uint32(0); i < 4; i++ {
+			a, b, c, d := cpuid(0x80860003 + i)
+			v = append(v, a, b, c, d)
+		}
+		rval.Info = strings.TrimSpace(string(valAsString(v...)))
+	}
+	return &rval
+}
+
+// vendorCapability describes which vendor specific CPUID leaves a vendor implements.
+type vendorCapability struct {
+	intelLeaves   bool // Intel defined cache and topology leaves (2, 4 and 0xB)
+	amdCompatible bool // AMD defined extended leaves, topology and cache information
+	centaurLeaves bool // Centaur leaves starting at 0xC0000000
+}
+
+// vendorCapabilities contains the vendors with vendor specific decoding.
+var vendorCapabilities = map[Vendor]vendorCapability{
+	Intel:   {intelLeaves: true},
+	AMD:     {amdCompatible: true},
+	Hygon:   {amdCompatible: true},
+	VIA:     {intelLeaves: true, centaurLeaves: true},
+	Zhaoxin: {intelLeaves: true, centaurLeaves: true},
+}
+
+// vendorCaps returns the capabilities of the current vendor.
+func vendorCaps() vendorCapability {
+	v, _ := vendorID()
+	return vendorCapabilities[v]
 }
 
 func vendorID() (Vendor, string) {
@@ -973,6 +2562,15 @@ func vendorID() (Vendor, string) {
 	return vend, v
 }
 
+// tdxGuest returns whether the CPU identifies as an Intel TDX guest (leaf 0x21).
+func tdxGuest() bool {
+	if maxFunctionID() < 0x21 {
+		return false
+	}
+	_, b, c, d := cpuidex(0x21, 0)
+	return string(valAsString(b, d, c)) == "IntelTDX    "
+}
+
 func cacheLine() int {
 	if maxFunctionID() < 0x1 {
 		return 0
@@ -993,10 +2591,15 @@ func (c *CPUInfo) cacheSize() {
 	c.Cache.L1I = -1
 	c.Cache.L2 = -1
 	c.Cache.L3 = -1
-	vendor, _ := vendorID()
-	switch vendor {
-	case Intel:
+	c.Cache.L1IWays = 0
+	c.Cache.L1DWays = 0
+	c.Cache.L2Ways = 0
+	c.Cache.L3Ways = 0
+	caps := vendorCaps()
+	switch {
+	case caps.intelLeaves:
 		if maxFunctionID() < 4 {
+			c.leaf2CacheSize()
 			return
 		}
 		for i := uint32(0); ; i++ {
@@ -1016,9 +2619,11 @@ func (c *CPUInfo) cacheSize() {
 				if cacheType == 1 {
 					// 1 = Data Cache
 					c.Cache.L1D = size
+					c.Cache.L1DWays = associativity
 				} else if cacheType == 2 {
 					// 2 = Instruction Cache
 					c.Cache.L1I = size
+					c.Cache.L1IWays = associativity
 				} else {
 					if c.Cache.L1D < 0 {
 						c.Cache.L1I = size
@@ -1029,24 +2634,33 @@ func (c *CPUInfo) cacheSize() {
 				}
 			case 2:
 				c.Cache.L2 = size
+				c.Cache.L2Ways = associativity
 			case 3:
 				c.Cache.L3 = size
+				c.Cache.L3Ways = associativity
 			}
 		}
-	case AMD, Hygon:
-		// Untested.
+	case caps.amdCompatible:
 		if maxExtendedFunction() < 0x80000005 {
 			return
 		}
 		_, _, ecx, edx := cpuid(0x80000005)
 		c.Cache.L1D = int(((ecx >> 24) & 0xFF) * 1024)
+		c.Cache.L1DWays = amdL1Ways(c.Cache.L1D, ecx)
 		c.Cache.L1I = int(((edx >> 24) & 0xFF) * 1024)
+		c.Cache.L1IWays = amdL1Ways(c.Cache.L1I, edx)
 
 		if maxExtendedFunction() < 0x80000006 {
 			return
 		}
-		_, _, ecx, _ = cpuid(0x80000006)
+		_, _, ecx, edx = cpuid(0x80000006)
 		c.Cache.L2 = int(((ecx >> 16) & 0xFFFF) * 1024)
+		c.Cache.L2Ways = amdL2L3Ways(c.Cache.L2, ecx)
+		// L3 size is reported in 512KB units. Zero if there is no L3.
+		if l3 := int((edx>>18)&0x3FFF) * 512 * 1024; l3 > 0 {
+			c.Cache.L3 = l3
+			c.Cache.L3Ways = amdL2L3Ways(l3, edx)
+		}
 
 		// CPUID Fn8000_001D_EAX_x[N:0] Cache Properties
 		if maxExtendedFunction() < 0x8000001D {
@@ -1067,15 +2681,18 @@ func (c *CPUInfo) cacheSize() {
 				return
 			}
 
+			ways := int(cacheNumWays)
 			switch level {
 			case 1:
 				switch typ {
 				case 1:
 					// Data cache
 					c.Cache.L1D = size
+					c.Cache.L1DWays = ways
 				case 2:
 					// Inst cache
 					c.Cache.L1I = size
+					c.Cache.L1IWays = ways
 				default:
 					if c.Cache.L1D < 0 {
 						c.Cache.L1I = size
@@ -1086,74 +2703,804 @@ func (c *CPUInfo) cacheSize() {
 				}
 			case 2:
 				c.Cache.L2 = size
+				c.Cache.L2Ways = ways
 			case 3:
 				c.Cache.L3 = size
+				c.Cache.L3Ways = ways
+			}
+		}
+	}
+
+	return
+}
+
+// amdL1Ways returns the associativity of an L1 cache from CPUID 0x80000005 ECX or EDX.
+func amdL1Ways(size int, reg uint32) int {
+	ways := (reg >> 16) & 0xFF
+	if ways == 0xFF {
+		return amdFullyAssociative(size, reg)
+	}
+	return int(ways)
+}
+
+// amdL2L3Associativity maps the associativity field of CPUID 0x80000006 ECX and EDX to the number of ways.
+// 0 is returned for disabled caches and reserved values.
+// 0x9 means the information is only available in leaf 0x8000001D.
+var amdL2L3Associativity = [16]int{0, 1, 2, 3, 4, 6, 8, 0, 16, 0, 32, 48, 64, 96, 128, 0}
+
+// amdL2L3Ways returns the associativity of an L2 or L3 cache from CPUID 0x80000006 ECX or EDX.
+func amdL2L3Ways(size int, reg uint32) int {
+	assoc := (reg >> 12) & 0xF
+	if assoc == 0xF {
+		return amdFullyAssociative(size, reg)
+	}
+	return amdL2L3Associativity[assoc]
+}
+
+// amdFullyAssociative returns the number of lines in a fully associative cache.
+// The line size is in the low byte of reg.
+func amdFullyAssociative(size int, reg uint32) int {
+	line := int(reg & 0xFF)
+	if line == 0 {
+		return 0
+	}
+	return size / line
+}
+
+// leaf2Descriptor describes a cache or TLB reported by a CPUID leaf 2 descriptor byte.
+type leaf2Descriptor struct {
+	level   int // Cache level. 0 for TLBs.
+	typ     int // 1 = Data, 2 = Instruction, 3 = Unified, as in leaf 4
+	size    int // Cache size in KB
+	ways    int // Associativity. 0 if fully associative.
+	line    int // Cache line size in bytes
+	entries int // Number of TLB entries
+}
+
+// leaf2Descriptors contains the known leaf 2 descriptors.
+// Trace caches and prefetch hints are not included.
+// Source: Intel SDM Vol. 2A, Table 3-12.
+var leaf2Descriptors = map[byte]leaf2Descriptor{
+	0x01: {typ: 2, ways: 4, entries: 32},
+	0x02: {typ: 2, ways: 0, entries: 2},
+	0x03: {typ: 1, ways: 4, entries: 64},
+	0x04: {typ: 1, ways: 4, entries: 8},
+	0x05: {typ: 1, ways: 4, entries: 32},
+	0x06: {level: 1, typ: 2, size: 8, ways: 4, line: 32},
+	0x08: {level: 1, typ: 2, size: 16, ways: 4, line: 32},
+	0x09: {level: 1, typ: 2, size: 32, ways: 4, line: 64},
+	0x0a: {level: 1, typ: 1, size: 8, ways: 2, line: 32},
+	0x0b: {typ: 2, ways: 4, entries: 4},
+	0x0c: {level: 1, typ: 1, size: 16, ways: 4, line: 32},
+	0x0d: {level: 1, typ: 1, size: 16, ways: 4, line: 64},
+	0x0e: {level: 1, typ: 1, size: 24, ways: 6, line: 64},
+	0x1d: {level: 2, typ: 3, size: 128, ways: 2, line: 64},
+	0x21: {level: 2, typ: 3, size: 256, ways: 8, line: 64},
+	0x22: {level: 3, typ: 3, size: 512, ways: 4, line: 64},
+	0x23: {level: 3, typ: 3, size: 1024, ways: 8, line: 64},
+	0x24: {level: 2, typ: 3, size: 1024, ways: 16, line: 64},
+	0x25: {level: 3, typ: 3, size: 2048, ways: 8, line: 64},
+	0x29: {level: 3, typ: 3, size: 4096, ways: 8, line: 64},
+	0x2c: {level: 1, typ: 1, size: 32, ways: 8, line: 64},
+	0x30: {level: 1, typ: 2, size: 32, ways: 8, line: 64},
+	0x39: {level: 2, typ: 3, size: 128, ways: 4, line: 64},
+	0x3a: {level: 2, typ: 3, size: 192, ways: 6, line: 64},
+	0x3b: {level: 2, typ: 3, size: 128, ways: 2, line: 64},
+	0x3c: {level: 2, typ: 3, size: 256, ways: 4, line: 64},
+	0x3d: {level: 2, typ: 3, size: 384, ways: 6, line: 64},
+	0x3e: {level: 2, typ: 3, size: 512, ways: 4, line: 64},
+	0x41: {level: 2, typ: 3, size: 128, ways: 4, line: 32},
+	0x42: {level: 2, typ: 3, size: 256, ways: 4, line: 32},
+	0x43: {level: 2, typ: 3, size: 512, ways: 4, line: 32},
+	0x44: {level: 2, typ: 3, size: 1024, ways: 4, line: 32},
+	0x45: {level: 2, typ: 3, size: 2048, ways: 4, line: 32},
+	0x46: {level: 3, typ: 3, size: 4096, ways: 4, line: 64},
+	0x47: {level: 3, typ: 3, size: 8192, ways: 8, line: 64},
+	0x48: {level: 2, typ: 3, size: 3072, ways: 12, line: 64},
+	0x49: {level: 2, typ: 3, size: 4096, ways: 16, line: 64},
+	0x4a: {level: 3, typ: 3, size: 6144, ways: 12, line: 64},
+	0x4b: {level: 3, typ: 3, size: 8192, ways: 16, line: 64},
+	0x4c: {level: 3, typ: 3, size: 12288, ways: 12, line: 64},
+	0x4d: {level: 3, typ: 3, size: 16384, ways: 16, line: 64},
+	0x4e: {level: 2, typ: 3, size: 6144, ways: 24, line: 64},
+	0x4f: {typ: 2, ways: 0, entries: 32},
+	0x50: {typ: 2, ways: 0, entries: 64},
+	0x51: {typ: 2, ways: 0, entries: 128},
+	0x52: {typ: 2, ways: 0, entries: 256},
+	0x55: {typ: 2, ways: 0, entries: 7},
+	0x56: {typ: 1, ways: 4, entries: 16},
+	0x57: {typ: 1, ways: 4, entries: 16},
+	0x59: {typ: 1, ways: 0, entries: 16},
+	0x5a: {typ: 1, ways: 4, entries: 32},
+	0x5b: {typ: 1, ways: 0, entries: 64},
+	0x5c: {typ: 1, ways: 0, entries: 128},
+	0x5d: {typ: 1, ways: 0, entries: 256},
+	0x60: {level: 1, typ: 1, size: 16, ways: 8, line: 64},
+	0x61: {typ: 2, ways: 0, entries: 48},
+	0x63: {typ: 1, ways: 4, entries: 32},
+	0x66: {level: 1, typ: 1, size: 8, ways: 4, line: 64},
+	0x67: {level: 1, typ: 1, size: 16, ways: 4, line: 64},
+	0x68: {level: 1, typ: 1, size: 32, ways: 4, line: 64},
+	0x6a: {typ: 1, ways: 8, entries: 64},
+	0x6b: {typ: 1, ways: 8, entries: 256},
+	0x6c: {typ: 1, ways: 8, entries: 128},
+	0x6d: {typ: 1, ways: 0, entries: 16},
+	0x76: {typ: 2, ways: 0, entries: 8},
+	0x78: {level: 2, typ: 3, size: 1024, ways: 4, line: 64},
+	0x79: {level: 2, typ: 3, size: 128, ways: 8, line: 64},
+	0x7a: {level: 2, typ: 3, size: 256, ways: 8, line: 64},
+	0x7b: {level: 2, typ: 3, size: 512, ways: 8, line: 64},
+	0x7c: {level: 2, typ: 3, size: 1024, ways: 8, line: 64},
+	0x7d: {level: 2, typ: 3, size: 2048, ways: 8, line: 64},
+	0x7f: {level: 2, typ: 3, size: 512, ways: 2, line: 64},
+	0x80: {level: 2, typ: 3, size: 512, ways: 8, line: 64},
+	0x82: {level: 2, typ: 3, size: 256, ways: 8, line: 32},
+	0x83: {level: 2, typ: 3, size: 512, ways: 8, line: 32},
+	0x84: {level: 2, typ: 3, size: 1024, ways: 8, line: 32},
+	0x85: {level: 2, typ: 3, size: 2048, ways: 8, line: 32},
+	0x86: {level: 2, typ: 3, size: 512, ways: 4, line: 64},
+	0x87: {level: 2, typ: 3, size: 1024, ways: 8, line: 64},
+	0xa0: {typ: 1, ways: 0, entries: 32},
+	0xb0: {typ: 2, ways: 4, entries: 128},
+	0xb1: {typ: 2, ways: 4, entries: 8},
+	0xb2: {typ: 2, ways: 4, entries: 64},
+	0xb3: {typ: 1, ways: 4, entries: 128},
+	0xb4: {typ: 1, ways: 4, entries: 256},
+	0xb5: {typ: 2, ways: 8, entries: 64},
+	0xb6: {typ: 2, ways: 8, entries: 128},
+	0xba: {typ: 1, ways: 4, entries: 64},
+	0xc0: {typ: 1, ways: 4, entries: 8},
+	0xc1: {typ: 3, ways: 8, entries: 1024},
+	0xc2: {typ: 1, ways: 4, entries: 16},
+	0xc3: {typ: 3, ways: 6, entries: 1536},
+	0xc4: {typ: 1, ways: 4, entries: 32},
+	0xca: {typ: 3, ways: 4, entries: 512},
+	0xd0: {level: 3, typ: 3, size: 512, ways: 4, line: 64},
+	0xd1: {level: 3, typ: 3, size: 1024, ways: 4, line: 64},
+	0xd2: {level: 3, typ: 3, size: 2048, ways: 4, line: 64},
+	0xd6: {level: 3, typ: 3, size: 1024, ways: 8, line: 64},
+	0xd7: {level: 3, typ: 3, size: 2048, ways: 8, line: 64},
+	0xd8: {level: 3, typ: 3, size: 4096, ways: 8, line: 64},
+	0xdc: {level: 3, typ: 3, size: 1536, ways: 12, line: 64},
+	0xdd: {level: 3, typ: 3, size: 3072, ways: 12, line: 64},
+	0xde: {level: 3, typ: 3, size: 6144, ways: 12, line: 64},
+	0xe2: {level: 3, typ: 3, size: 2048, ways: 16, line: 64},
+	0xe3: {level: 3, typ: 3, size: 4096, ways: 16, line: 64},
+	0xe4: {level: 3, typ: 3, size: 8192, ways: 16, line: 64},
+	0xea: {level: 3, typ: 3, size: 12288, ways: 24, line: 64},
+	0xeb: {level: 3, typ: 3, size: 18432, ways: 24, line: 64},
+	0xec: {level: 3, typ: 3, size: 24576, ways: 24, line: 64},
+}
+
+// leaf2 returns the descriptors reported by CPUID leaf 2.
+// ok is false if the processor reports 0xFF, meaning that leaf 4 must be used instead.
+func leaf2() (desc []leaf2Descriptor, ok bool) {
+	if maxFunctionID() < 2 {
+		return nil, false
+	}
+	family, model := familyModel()
+	// The low byte of eax is the number of times leaf 2 must be queried.
+	for i, n := 0, 1; i < n; i++ {
+		eax, ebx, ecx, edx := cpuid(2)
+		if i == 0 {
+			n = int(eax & 0xff)
+		}
+		eax &^= 0xff
+		for _, reg := range []uint32{eax, ebx, ecx, edx} {
+			// Bit 31 set means the register contains no descriptors.
+			if reg&(1<<31) != 0 {
+				continue
+			}
+			for ; reg != 0; reg >>= 8 {
+				b := byte(reg)
+				if b == 0xff {
+					return nil, false
+				}
+				d, found := leaf2Descriptors[b]
+				if !found {
+					continue
+				}
+				// Pentium 4 family 0Fh, model 06h reports its L3 as 0x49.
+				if b == 0x49 && family == 15 && model == 6 {
+					d.level = 3
+				}
+				desc = append(desc, d)
+			}
+		}
+	}
+	return desc, true
+}
+
+// leaf2CacheSize fills the cache sizes from CPUID leaf 2.
+// It is used on Intel processors that do not support leaf 4.
+func (c *CPUInfo) leaf2CacheSize() {
+	desc, ok := leaf2()
+	if !ok {
+		return
+	}
+	for _, d := range desc {
+		size := d.size * 1024
+		switch d.level {
+		case 1:
+			if d.typ == 2 {
+				c.Cache.L1I = size
+				c.Cache.L1IWays = d.ways
+			} else {
+				c.Cache.L1D = size
+				c.Cache.L1DWays = d.ways
+			}
+		case 2:
+			c.Cache.L2 = size
+			c.Cache.L2Ways = d.ways
+		case 3:
+			c.Cache.L3 = size
+			c.Cache.L3Ways = d.ways
+		}
+	}
+}
+
+type SGXEPCSection struct {
+	BaseAddress uint64
+	EPCSize     uint64
+}
+
+type SGXSupport struct {
+	Available           bool
+	LaunchControl       bool
+	SGX1Supported       bool
+	SGX2Supported       bool
+	EDMMSupported       bool   // Enclave Dynamic Memory Management (SGX2 instructions)
+	ENCLVSupported      bool   // ENCLV leaves for oversubscription of EPC in guests
+	ENCLSCSupported     bool   // ENCLS-C leaves (ETRACKC, ERDINFO, ELDBC, ELDUC)
+	KSSSupported        bool   // Key Separation and Sharing
+	AEXNotifySupported  bool   // AEX-Notify and EDECCSSA
+	MiscSelect          uint32 // Supported bits of MISCSELECT
+	Attributes          uint64 // Bits that may be set in SECS.ATTRIBUTES
+	XFRM                uint64 // Bits that may be set in SECS.ATTRIBUTES.XFRM
+	MaxEnclaveSizeNot64 int64
+	MaxEnclaveSize64    int64
+	EPCSections         []SGXEPCSection
+	EPCSize             uint64 // Total size of all EPC sections
+
+	// Linux only: Presence of the kernel device nodes.
+	EnclaveDevice   bool // /dev/sgx_enclave exists
+	ProvisionDevice bool // /dev/sgx_provision exists
+	// Linux only: EPC memory in bytes as reported by the kernel, summed over all NUMA nodes.
+	KernelEPCSize uint64
+}
+
+func hasSGX(available, lc bool) (rval SGXSupport) {
+	rval.Available = available
+
+	if !available {
+		return
+	}
+
+	rval.LaunchControl = lc
+
+	a, b, _, d := cpuidex(0x12, 0)
+	rval.SGX1Supported = a&0x01 != 0
+	rval.SGX2Supported = a&0x02 != 0
+	rval.EDMMSupported = rval.SGX2Supported
+	rval.ENCLVSupported = a&(1<<5) != 0
+	rval.ENCLSCSupported = a&(1<<6) != 0
+	rval.MiscSelect = b
+	rval.MaxEnclaveSizeNot64 = 1 << (d & 0xFF)     // pow 2
+	rval.MaxEnclaveSize64 = 1 << ((d >> 8) & 0xFF) // pow 2
+	rval.EPCSections = make([]SGXEPCSection, 0)
+
+	// SECS.ATTRIBUTES[63:0] in EBX:EAX, XFRM in EDX:ECX.
+	a1, b1, c1, d1 := cpuidex(0x12, 1)
+	rval.Attributes = uint64(a1) | uint64(b1)<<32
+	rval.XFRM = uint64(c1) | uint64(d1)<<32
+	rval.KSSSupported = a1&(1<<7) != 0
+	rval.AEXNotifySupported = a1&(1<<10) != 0 && a&(1<<11) != 0
+
+	for subleaf := uint32(2); subleaf < 2+8; subleaf++ {
+		eax, ebx, ecx, edx := cpuidex(0x12, subleaf)
+		leafType := eax & 0xf
+
+		if leafType == 0 {
+			// Invalid subleaf, stop iterating
+			break
+		} else if leafType == 1 {
+			// EPC Section subleaf
+			baseAddress := uint64(eax&0xfffff000) + (uint64(ebx&0x000fffff) << 32)
+			size := uint64(ecx&0xfffff000) + (uint64(edx&0x000fffff) << 32)
+
+			section := SGXEPCSection{BaseAddress: baseAddress, EPCSize: size}
+			rval.EPCSections = append(rval.EPCSections, section)
+			rval.EPCSize += size
+		}
+	}
+
+	rval.EnclaveDevice = osFileExists(devRoot, "sgx_enclave")
+	rval.ProvisionDevice = osFileExists(devRoot, "sgx_provision")
+	rval.KernelEPCSize = osSGXTotalBytes()
+
+	return
+}
+
+// KeyLocker contains Intel Key Locker capabilities (leaf 0x19).
+type KeyLocker struct {
+	Available   bool // Key Locker is supported (CPUID.7.0:ECX[23])
+	AESKLE      bool // AES Key Locker instructions are enabled by the OS
+	WideKL      bool // AES wide Key Locker instructions are supported and enabled by the OS (requires AESKLE)
+	IWKeyBackup bool // Internal wrapping key backup MSRs are supported
+	NoBackup    bool // LOADIWKEY NoBackup parameter is supported
+	RandomIWKey bool // Random internal wrapping keys are supported (KeySource 1)
+
+	// Restrictions on handles supported by the processor.
+	CPL0Only  bool // Handles restricted to CPL0
+	NoEncrypt bool // Handles restricted to decryption
+	NoDecrypt bool // Handles restricted to encryption
+}
+
+func keyLocker(available bool) (rval KeyLocker) {
+	rval.Available = available
+	if !available || maxFunctionID() < 0x19 {
+		return
+	}
+	a, b, c, _ := cpuid(0x19)
+	rval.CPL0Only = a&1 != 0
+	rval.NoEncrypt = a&(1<<1) != 0
+	rval.NoDecrypt = a&(1<<2) != 0
+	rval.AESKLE = b&1 != 0
+	rval.WideKL = rval.AESKLE && b&(1<<2) != 0
+	rval.IWKeyBackup = b&(1<<4) != 0
+	rval.NoBackup = c&1 != 0
+	rval.RandomIWKey = c&(1<<1) != 0
+	return
+}
+
+// PMU contains information about the performance monitoring unit.
+// Counter counts are per logical processor.
+type PMU struct {
+	Version           int    // Architectural performance monitoring version (Intel). 0 if not supported.
+	GPCounters        int    // Number of general-purpose counters.
+	GPCounterWidth    int    // Bit width of general-purpose counters.
+	FixedCounters     int    // Number of fixed-function counters (Intel).
+	FixedCounterWidth int    // Bit width of fixed-function counters (Intel).
+	FixedCounterMask  uint32 // Bitmap of supported fixed-function counters (Intel, version 5+).
+	EventsLength      int    // Number of valid bits in UnavailableEvents.
+	UnavailableEvents uint32 // Bitmap of architectural events that are NOT available (Intel).
+	PerfMonV2         bool   // AMD Performance Monitoring Version 2
+	NBCounters        int    // Number of Northbridge/Data Fabric counters (AMD).
+	UMCCounters       int    // Number of Unified Memory Controller counters (AMD).
+	LBR               LBRSupport
+	IBS               IBSSupport
+}
+
+// LBRSupport contains last branch record capabilities.
+// On Intel this is architectural LBR (leaf 0x1C),
+// on AMD this is LbrExtV2 (leaf 0x80000022).
+type LBRSupport struct {
+	Available       bool
+	Depths          []int // Supported LBR stack depths (Intel). AMD reports a single depth.
+	DeepCStateReset bool  // LBRs may be cleared on deep C-state exit
+	IPIsLIP         bool  // LBR IP values contain LIP rather than EIP
+	CPLFiltering    bool  // Supports CPL filtering
+	BranchFiltering bool  // Supports branch filtering
+	CallStack       bool  // Supports call-stack mode
+	Mispredict      bool  // Supports mispredict bit
+	TimedLBR        bool  // Supports timed LBRs
+	BranchType      bool  // Supports branch type field
+	Freeze          bool  // Supports freezing LBRs and counters on PMI (AMD)
+}
+
+// IBSSupport contains AMD Instruction Based Sampling capabilities (leaf 0x8000001B).
+type IBSSupport struct {
+	Available     bool
+	FetchSampling bool // IBS fetch sampling supported
+	OpSampling    bool // IBS execution sampling supported
+	RdWrOpCnt     bool // Read write of op counter supported
+	OpCnt         bool // Op counting mode supported
+	BrnTrgt       bool // Branch target address reporting supported
+	OpCntExt      bool // IbsOpCurCnt and IbsOpMaxCnt extend by 7 bits
+	RipInvalidChk bool // Invalid RIP indication supported
+	OpBrnFuse     bool // Fused branch micro-op indication supported
+	FetchCtlExtd  bool // IBS fetch control extended MSR supported
+	OpData4       bool // IBS op data 4 MSR supported
+	L3MissFilter  bool // L3 miss filtering supported
+}
+
+func pmu() (rval PMU) {
+	mfi := maxFunctionID()
+	mxf := maxExtendedFunction()
+
+	if mfi >= 0xa {
+		a, b, c, d := cpuid(0xa)
+		rval.Version = int(a & 0xff)
+		if rval.Version > 0 {
+			rval.GPCounters = int((a >> 8) & 0xff)
+			rval.GPCounterWidth = int((a >> 16) & 0xff)
+			rval.EventsLength = int((a >> 24) & 0xff)
+			rval.UnavailableEvents = b
+			if rval.EventsLength < 32 {
+				rval.UnavailableEvents &= (1 << uint(rval.EventsLength)) - 1
+			}
+			if rval.Version > 1 {
+				rval.FixedCounters = int(d & 0x1f)
+				rval.FixedCounterWidth = int((d >> 5) & 0xff)
+			}
+			if rval.Version >= 5 {
+				rval.FixedCounterMask = c
+			}
+		}
+	}
+
+	if mfi >= 0x1c {
+		_, _, _, edx := cpuidex(7, 0)
+		// Architectural LBR
+		if edx&(1<<19) != 0 {
+			a, b, c, _ := cpuid(0x1c)
+			for i := uint(0); i < 8; i++ {
+				if a&(1<<i) != 0 {
+					rval.LBR.Depths = append(rval.LBR.Depths, 8*int(i+1))
+				}
 			}
+			rval.LBR.Available = len(rval.LBR.Depths) > 0
+			rval.LBR.DeepCStateReset = a&(1<<30) != 0
+			rval.LBR.IPIsLIP = a&(1<<31) != 0
+			rval.LBR.CPLFiltering = b&1 != 0
+			rval.LBR.BranchFiltering = b&(1<<1) != 0
+			rval.LBR.CallStack = b&(1<<2) != 0
+			rval.LBR.Mispredict = c&1 != 0
+			rval.LBR.TimedLBR = c&(1<<1) != 0
+			rval.LBR.BranchType = c&(1<<2) != 0
 		}
 	}
 
+	if !vendorCaps().amdCompatible {
+		return
+	}
+	if mxf < 0x80000001 {
+		return
+	}
+	_, _, c, _ := cpuid(0x80000001)
+	rval.GPCounters = 4
+	if c&(1<<23) != 0 {
+		// PerfCtrExtCore
+		rval.GPCounters = 6
+	}
+	if c&(1<<24) != 0 {
+		// PerfCtrExtNB
+		rval.NBCounters = 4
+	}
+	rval.GPCounterWidth = 48
+
+	// CPUID Fn8000_001B Instruction Based Sampling Identifiers
+	if c&(1<<10) != 0 && mxf >= 0x8000001b {
+		a, _, _, _ := cpuid(0x8000001b)
+		rval.IBS.Available = a&1 != 0
+		rval.IBS.FetchSampling = a&(1<<1) != 0
+		rval.IBS.OpSampling = a&(1<<2) != 0
+		rval.IBS.RdWrOpCnt = a&(1<<3) != 0
+		rval.IBS.OpCnt = a&(1<<4) != 0
+		rval.IBS.BrnTrgt = a&(1<<5) != 0
+		rval.IBS.OpCntExt = a&(1<<6) != 0
+		rval.IBS.RipInvalidChk = a&(1<<7) != 0
+		rval.IBS.OpBrnFuse = a&(1<<8) != 0
+		rval.IBS.FetchCtlExtd = a&(1<<9) != 0
+		rval.IBS.OpData4 = a&(1<<10) != 0
+		rval.IBS.L3MissFilter = a&(1<<11) != 0
+	}
+
+	// CPUID Fn8000_0022 Extended Performance Monitoring and Debug
+	if mxf >= 0x80000022 {
+		a, b, _, _ := cpuid(0x80000022)
+		rval.PerfMonV2 = a&1 != 0
+		if rval.PerfMonV2 {
+			rval.GPCounters = int(b & 0xf)
+			rval.NBCounters = int((b >> 10) & 0x3f)
+			rval.UMCCounters = int((b >> 16) & 0x3f)
+		}
+		if a&(1<<1) != 0 {
+			rval.LBR.Available = true
+			rval.LBR.Depths = []int{int((b >> 4) & 0x3f)}
+			rval.LBR.Freeze = a&(1<<2) != 0
+		}
+	}
 	return
 }
 
-type SGXEPCSection struct {
-	BaseAddress uint64
-	EPCSize     uint64
+// SEVSupport contains AMD memory encryption capabilities (leaf 0x8000001F).
+type SEVSupport struct {
+	SME                bool // Secure Memory Encryption
+	SEV                bool // Secure Encrypted Virtualization
+	SEVES              bool // SEV Encrypted State
+	SEVSNP             bool // SEV Secure Nested Paging
+	VMPL               bool // VM Permission Levels
+	CBitPosition       int  // Page table bit position used to indicate encryption
+	PhysAddrReduction  int  // Reduction of physical address space in bits when memory encryption is enabled
+	NumVMPL            int  // Number of VM Permission Levels supported
+	NumEncryptedGuests int  // Number of encrypted guests supported simultaneously
+	MinSEVASID         int  // Minimum ASID value for an SEV enabled, SEV-ES disabled guest
+
+	// Memory encryption in effect when running as a guest.
+	// Only detected on Linux. See sevGuestState for the sources used.
+	GuestSEV    bool
+	GuestSEVES  bool
+	GuestSEVSNP bool
 }
 
-type SGXSupport struct {
-	Available           bool
-	LaunchControl       bool
-	SGX1Supported       bool
-	SGX2Supported       bool
-	MaxEnclaveSizeNot64 int64
-	MaxEnclaveSize64    int64
-	EPCSections         []SGXEPCSection
+// Active returns whether memory encryption is in effect in the guest we are running in.
+// This is a best-effort hint and not an attestation of the guest.
+func (s SEVSupport) Active() bool {
+	return s.GuestSEV || s.GuestSEVES || s.GuestSEVSNP
 }
 
-func hasSGX(available, lc bool) (rval SGXSupport) {
-	rval.Available = available
+func sev() (rval SEVSupport) {
+	if !vendorCaps().amdCompatible {
+		return
+	}
+	if maxExtendedFunction() < 0x8000001f {
+		return
+	}
+	a, b, c, d := cpuid(0x8000001f)
+	rval.SME = a&1 != 0
+	rval.SEV = a&(1<<1) != 0
+	rval.SEVES = a&(1<<3) != 0
+	rval.SEVSNP = a&(1<<4) != 0
+	rval.VMPL = a&(1<<5) != 0
+	if rval.SME || rval.SEV {
+		rval.CBitPosition = int(b & 0x3f)
+		rval.PhysAddrReduction = int((b >> 6) & 0x3f)
+	}
+	if rval.VMPL {
+		rval.NumVMPL = int((b >> 12) & 0xf)
+	}
+	if rval.SEV {
+		rval.NumEncryptedGuests = int(c)
+		rval.MinSEVASID = int(d)
+	}
 
-	if !available {
+	// Check if we are a guest.
+	_, _, c1, _ := cpuid(1)
+	if c1&(1<<31) == 0 || !rval.SEV {
 		return
 	}
+	rval.GuestSEV, rval.GuestSEVES, rval.GuestSEVSNP = sevGuestState(rval)
+	return
+}
 
-	rval.LaunchControl = lc
+// msrSEVStatus is the SEV_STATUS MSR, which reports the memory encryption
+// enabled for the guest in bits 0 (SEV), 1 (SEV-ES) and 2 (SEV-SNP).
+const msrSEVStatus = 0xc0010131
 
-	a, _, _, d := cpuidex(0x12, 0)
-	rval.SGX1Supported = a&0x01 != 0
-	rval.SGX2Supported = a&0x02 != 0
-	rval.MaxEnclaveSizeNot64 = 1 << (d & 0xFF)     // pow 2
-	rval.MaxEnclaveSize64 = 1 << ((d >> 8) & 0xFF) // pow 2
-	rval.EPCSections = make([]SGXEPCSection, 0)
+// sevGuestState returns the memory encryption in effect in the guest,
+// limited to what CPUID leaf 0x8000001F reports as supported.
+//
+// SEV_STATUS is used if it can be read through the msr driver.
+// Otherwise the state reported by the kernel is used: the sev, sev_es
+// and sev_snp flags in /proc/cpuinfo, and /dev/sev-guest, which is only
+// created in SNP guests when the sev-guest driver is loaded.
+//
+// The result is a best-effort hint. Privileged code in the guest can
+// change what is reported, and the sources may be unavailable when
+// encryption is in effect. Use attestation to verify the guest.
+func sevGuestState(s SEVSupport) (sev, es, snp bool) {
+	if status, ok := osReadMSR(msrSEVStatus); ok {
+		snp = s.SEVSNP && status&(1<<2) != 0
+		es = snp || (s.SEVES && status&(1<<1) != 0)
+		return es || status&1 != 0, es, snp
+	}
+	flags := osCPUFlags()
+	snp = s.SEVSNP && (flags["sev_snp"] || osFileExists(devRoot, "sev-guest"))
+	es = snp || (s.SEVES && flags["sev_es"])
+	return es || flags["sev"], es, snp
+}
 
-	for subleaf := uint32(2); subleaf < 2+8; subleaf++ {
-		eax, ebx, ecx, edx := cpuidex(0x12, subleaf)
-		leafType := eax & 0xf
+// SVMSupport contains AMD Secure Virtual Machine capabilities (leaf 0x8000000A).
+// It is only filled when the SVM feature is set.
+type SVMSupport struct {
+	Revision             int  // SVM revision number
+	NumASID              int  // Number of available address space identifiers
+	NPT                  bool // Nested paging
+	LBRVirt              bool // LBR virtualization
+	SVML                 bool // SVM lock
+	NRIPSave             bool // NRIP save on #VMEXIT
+	TSCRateMSR           bool // MSR based TSC rate control
+	VMCBClean            bool // VMCB clean bits
+	FlushByASID          bool // Flush by ASID
+	DecodeAssists        bool // Decode assists
+	PauseFilter          bool // Pause intercept filter
+	PauseFilterThreshold bool // Pause filter threshold
+	AVIC                 bool // Advanced virtual interrupt controller
+	VMSAVEVirt           bool // VMSAVE and VMLOAD virtualization
+	VGIF                 bool // Virtualized global interrupt flag
+	GMET                 bool // Guest mode execution trap
+	X2AVIC               bool // Virtual x2APIC
+	SSS                  bool // Supervisor shadow stack restrictions
+}
 
-		if leafType == 0 {
-			// Invalid subleaf, stop iterating
-			break
-		} else if leafType == 1 {
-			// EPC Section subleaf
-			baseAddress := uint64(eax&0xfffff000) + (uint64(ebx&0x000fffff) << 32)
-			size := uint64(ecx&0xfffff000) + (uint64(edx&0x000fffff) << 32)
+func svm() (rval SVMSupport) {
+	if !vendorCaps().amdCompatible {
+		return
+	}
+	if maxExtendedFunction() < 0x8000000a {
+		return
+	}
+	_, _, c, _ := cpuid(0x80000001)
+	if c&(1<<2) == 0 {
+		return
+	}
+	a, b, _, d := cpuid(0x8000000a)
+	rval.Revision = int(a & 0xff)
+	rval.NumASID = int(b)
+	rval.NPT = d&1 != 0
+	rval.LBRVirt = d&(1<<1) != 0
+	rval.SVML = d&(1<<2) != 0
+	rval.NRIPSave = d&(1<<3) != 0
+	rval.TSCRateMSR = d&(1<<4) != 0
+	rval.VMCBClean = d&(1<<5) != 0
+	rval.FlushByASID = d&(1<<6) != 0
+	rval.DecodeAssists = d&(1<<7) != 0
+	rval.PauseFilter = d&(1<<10) != 0
+	rval.PauseFilterThreshold = d&(1<<12) != 0
+	rval.AVIC = d&(1<<13) != 0
+	rval.VMSAVEVirt = d&(1<<15) != 0
+	rval.VGIF = d&(1<<16) != 0
+	rval.GMET = d&(1<<17) != 0
+	rval.X2AVIC = d&(1<<18) != 0
+	rval.SSS = d&(1<<19) != 0
+	return
+}
 
-			section := SGXEPCSection{BaseAddress: baseAddress, EPCSize: size}
-			rval.EPCSections = append(rval.EPCSections, section)
+// ResourceDirector contains resource monitoring and allocation capabilities.
+// This is Intel Resource Director Technology (RDT) and AMD Platform Quality of Service (PQOS).
+// Each capability records the CPUID leaf it was read from.
+type ResourceDirector struct {
+	Monitoring RDTMonitoring // Cache and memory bandwidth monitoring
+	L3CAT      RDTAllocation // L3 cache allocation
+	L2CAT      RDTAllocation // L2 cache allocation
+	MBA        RDTBandwidth  // Memory bandwidth allocation
+}
+
+// RDTMonitoring contains cache monitoring capabilities.
+type RDTMonitoring struct {
+	Leaf         uint32 // CPUID leaf supplying the information. 0 if not supported.
+	MaxRMID      int    // Highest RMID of any resource type
+	L3MaxRMID    int    // Highest RMID for L3 monitoring
+	Occupancy    bool   // L3 cache occupancy monitoring
+	TotalBW      bool   // Total memory bandwidth monitoring
+	LocalBW      bool   // Local memory bandwidth monitoring
+	ScaleFactor  int    // Conversion factor from counter value to bytes
+	CounterWidth int    // Width of the monitoring counters in bits
+}
+
+// RDTAllocation contains cache allocation capabilities.
+type RDTAllocation struct {
+	Leaf       uint32 // CPUID leaf supplying the information. 0 if not supported.
+	MaskLength int    // Length of the capacity bit mask
+	NumCOS     int    // Number of classes of service
+	CDP        bool   // Code and Data Prioritization
+}
+
+// RDTBandwidth contains memory bandwidth allocation capabilities.
+type RDTBandwidth struct {
+	Leaf        uint32 // CPUID leaf supplying the information. 0 if not supported.
+	NumCOS      int    // Number of classes of service
+	MaxThrottle int    // Maximum throttling value (Intel)
+	Linear      bool   // Response of the delay values is linear (Intel)
+	BWLength    int    // Length of the bandwidth specification field in bits (AMD)
+}
+
+func resourceDirector() (rval ResourceDirector) {
+	mfi := maxFunctionID()
+	if mfi < 7 {
+		return
+	}
+	_, ebx, _, _ := cpuidex(7, 0)
+
+	// CPUID.7.0:EBX[12] Resource Director Technology Monitoring
+	if ebx&(1<<12) != 0 && mfi >= 0xf {
+		_, b, _, d := cpuidex(0xf, 0)
+		rval.Monitoring.MaxRMID = int(b)
+		// L3 monitoring
+		if d&(1<<1) != 0 {
+			a, b, c, d := cpuidex(0xf, 1)
+			rval.Monitoring.Leaf = 0xf
+			rval.Monitoring.L3MaxRMID = int(c)
+			rval.Monitoring.Occupancy = d&1 != 0
+			rval.Monitoring.TotalBW = d&(1<<1) != 0
+			rval.Monitoring.LocalBW = d&(1<<2) != 0
+			rval.Monitoring.ScaleFactor = int(b)
+			rval.Monitoring.CounterWidth = 24 + int(a&0xff)
+		}
+	}
+
+	// CPUID.7.0:EBX[15] Resource Director Technology Allocation
+	if ebx&(1<<15) != 0 && mfi >= 0x10 {
+		_, b, _, _ := cpuidex(0x10, 0)
+		cat := func(sub uint32) RDTAllocation {
+			a, _, c, d := cpuidex(0x10, sub)
+			return RDTAllocation{
+				Leaf:       0x10,
+				MaskLength: int(a&0x1f) + 1,
+				NumCOS:     int(d&0xffff) + 1,
+				CDP:        c&(1<<2) != 0,
+			}
+		}
+		if b&(1<<1) != 0 {
+			rval.L3CAT = cat(1)
+		}
+		if b&(1<<2) != 0 {
+			rval.L2CAT = cat(2)
+		}
+		if b&(1<<3) != 0 {
+			a, _, c, d := cpuidex(0x10, 3)
+			rval.MBA.Leaf = 0x10
+			rval.MBA.MaxThrottle = int(a&0xfff) + 1
+			rval.MBA.Linear = c&(1<<2) != 0
+			rval.MBA.NumCOS = int(d&0xffff) + 1
 		}
 	}
 
+	if vendorCaps().amdCompatible && maxExtendedFunction() >= 0x80000020 {
+		// CPUID Fn8000_0020 Platform QoS Enforcement
+		_, b, _, _ := cpuidex(0x80000020, 0)
+		// L3 Memory Bandwidth Enforcement
+		if b&(1<<1) != 0 {
+			a, _, _, d := cpuidex(0x80000020, 1)
+			rval.MBA.Leaf = 0x80000020
+			rval.MBA.BWLength = int(a)
+			rval.MBA.NumCOS = int(d) + 1
+		}
+	}
 	return
 }
 
-func support() (Flags, AmxFlags) {
+// avx10 returns the AVX10 version and the vector lengths
+// supported by the CPU and enabled by the OS.
+func avx10() (version int, lengths []int) {
+	mfi := maxFunctionID()
+	if mfi < 0x24 {
+		return 0, nil
+	}
+	_, _, c, _ := cpuid(1)
+	// XGETBV and OSXSAVE
+	if c&((1<<26)|(1<<27)) != (1<<26)|(1<<27) {
+		return 0, nil
+	}
+	eax, _, _, _ := cpuidex(7, 0)
+	if eax < 1 {
+		return 0, nil
+	}
+	_, _, _, edx1 := cpuidex(7, 1)
+	if edx1&(1<<19) == 0 {
+		return 0, nil
+	}
+	// Check OS support of XMM, YMM and opmask state.
+	xcr0, _ := xgetbv(0)
+	if xcr0&0x26 != 0x26 {
+		return 0, nil
+	}
+	_, b, _, _ := cpuidex(0x24, 0)
+	version = int(b & 0xff)
+	if b&(1<<16) != 0 {
+		lengths = append(lengths, 128)
+	}
+	if b&(1<<17) != 0 {
+		lengths = append(lengths, 256)
+	}
+	// 512 bit vectors also require ZMM state to be enabled.
+	if b&(1<<18) != 0 && (xcr0>>5)&7 == 7 {
+		lengths = append(lengths, 512)
+	}
+	return version, lengths
+}
+
+func support() (Flags, AmxFlags, ExtFlags) {
 	mfi := maxFunctionID()
 	vend, _ := vendorID()
+	caps := vendorCapabilities[vend]
 	if mfi < 0x1 {
-		return 0, 0
+		return 0, 0, 0
 	}
 	flags := uint64(0)
 	amxFlags := AmxFlags(0)
+	extFlags := ExtFlags(0)
 	_, _, c, d := cpuid(1)
 	if (d & (1 << 15)) != 0 {
 		flags |= CMOV
@@ -1203,12 +3550,7 @@ func support() (Flags, AmxFlags) {
 	if c&(1<<13) != 0 {
 		flags |= CX16
 	}
-	if vend == Intel && (d&(1<<28)) != 0 && mfi >= 4 {
-		if threadsPerCore() > 1 {
-			flags |= HTT
-		}
-	}
-	if vend == AMD && (d&(1<<28)) != 0 && mfi >= 4 {
+	if (caps.intelLeaves || caps.amdCompatible) && (d&(1<<28)) != 0 && mfi >= 4 {
 		if threadsPerCore() > 1 {
 			flags |= HTT
 		}
@@ -1227,8 +3569,11 @@ func support() (Flags, AmxFlags) {
 
 	// Check AVX2, AVX2 requires OS support, but BMI1/2 don't.
 	if mfi >= 7 {
-		_, ebx, ecx, edx := cpuidex(7, 0)
-		eax1, _, _, _ := cpuidex(7, 1)
+		eax, ebx, ecx, edx := cpuidex(7, 0)
+		var eax1, edx1 uint32
+		if eax >= 1 {
+			eax1, _, _, edx1 = cpuidex(7, 1)
+		}
 		if (flags&AVX) != 0 && (ebx&0x00000020) != 0 {
 			flags |= AVX2
 		}
@@ -1271,6 +3616,117 @@ func support() (Flags, AmxFlags) {
 		if edx&(1<<27) != 0 {
 			flags |= STIBP
 		}
+		if ebx&(1<<23) != 0 {
+			extFlags |= CLFLUSHOPT
+		}
+		if ebx&(1<<24) != 0 {
+			extFlags |= CLWB
+		}
+		if ecx&(1<<0) != 0 {
+			extFlags |= PREFETCHWT1
+		}
+		if ecx&(1<<23) != 0 {
+			extFlags |= KEYLOCKER
+		}
+		if ecx&(1<<5) != 0 {
+			extFlags |= WAITPKG
+		}
+		if ecx&(1<<25) != 0 {
+			extFlags |= CLDEMOTE
+		}
+		if ecx&(1<<27) != 0 {
+			extFlags |= MOVDIRI
+		}
+		if ecx&(1<<28) != 0 {
+			extFlags |= MOVDIR64B
+		}
+		if ecx&(1<<29) != 0 {
+			extFlags |= ENQCMD
+		}
+		if edx&(1<<4) != 0 {
+			extFlags |= FSRM
+		}
+		if edx&(1<<5) != 0 {
+			extFlags |= UINTR
+		}
+		if edx&(1<<14) != 0 {
+			extFlags |= SERIALIZE
+		}
+		if edx&(1<<16) != 0 {
+			extFlags |= TSXLDTRK
+		}
+		if edx&(1<<18) != 0 {
+			extFlags |= PCONFIG
+		}
+
+		// cpuid eax 07h,ecx=1
+		if eax1&(1<<3) != 0 {
+			extFlags |= RAOINT
+		}
+		if eax1&(1<<7) != 0 {
+			extFlags |= CMPCCXADD
+		}
+		if eax1&(1<<10) != 0 {
+			extFlags |= FZLRM
+		}
+		if eax1&(1<<11) != 0 {
+			extFlags |= FSRS
+		}
+		if eax1&(1<<12) != 0 {
+			extFlags |= FSRCS
+		}
+		if eax1&(1<<19) != 0 {
+			extFlags |= WRMSRNS
+		}
+		if eax1&(1<<22) != 0 {
+			extFlags |= HRESET
+		}
+		if eax1&(1<<26) != 0 {
+			extFlags |= LAM
+		}
+		if eax1&(1<<27) != 0 {
+			extFlags |= MSRLIST
+		}
+		if edx1&(1<<14) != 0 {
+			extFlags |= PREFETCHI
+		}
+		if v, _ := avx10(); v > 0 {
+			extFlags |= AVX10
+		}
+		// APX requires OS support of the extended GPR state, XCR0[19].
+		if edx1&(1<<21) != 0 && c&((1<<26)|(1<<27)) == (1<<26)|(1<<27) {
+			if xcr0, _ := xgetbv(0); xcr0&(1<<19) != 0 {
+				extFlags |= APX
+			}
+		}
+
+		// VEX encoded instructions require OS support of YMM state.
+		if flags&AVX != 0 {
+			if eax1&(1<<0) != 0 {
+				extFlags |= SHA512X86
+			}
+			if eax1&(1<<1) != 0 {
+				extFlags |= SM3X86
+			}
+			if eax1&(1<<2) != 0 {
+				extFlags |= SM4X86
+			}
+			if eax1&(1<<4) != 0 {
+				extFlags |= AVXVNNI
+			}
+			if eax1&(1<<23) != 0 {
+				extFlags |= AVXIFMA
+			}
+			if edx1&(1<<4) != 0 {
+				extFlags |= AVXVNNIINT8
+			}
+			if edx1&(1<<5) != 0 {
+				extFlags |= AVXNECONVERT
+			}
+			if edx1&(1<<10) != 0 {
+				extFlags |= AVXVNNIINT16
+			}
+		}
 
 		// Only detect AVX-512 features if XGETBV is supported
 		if c&((1<<26)|(1<<27)) == (1<<26)|(1<<27) {
@@ -1334,6 +3790,16 @@ func support() (Flags, AmxFlags) {
 				if edx&(1<<8) != 0 {
 					flags |= AVX512VP2INTERSECT
 				}
+				if edx&(1<<23) != 0 {
+					extFlags |= AVX512FP16
+				}
+				// AVX10/512 implies the AVX-512 features included in AVX10.1.
+				if v, lengths := avx10(); v > 0 && len(lengths) > 0 && lengths[len(lengths)-1] == 512 {
+					flags |= AVX512F | AVX512CD | AVX512BW | AVX512DQ | AVX512VL
+					flags |= AVX512IFMA | AVX512VBMI | AVX512VBMI2 | AVX512VNNI
+					flags |= AVX512BITALG | AVX512VPOPCNTDQ | AVX512BF16
+					extFlags |= AVX512FP16
+				}
 				if edx&(1<<22) != 0 {
 					amxFlags |= AMXBF16
 				}
@@ -1353,9 +3819,13 @@ func support() (Flags, AmxFlags) {
 
 	if maxExtendedFunction() >= 0x80000001 {
 		_, _, c, d := cpuid(0x80000001)
+		// Intel reports LZCNT here. AMD calls the bit ABM,
+		// which also covers POPCNT on parts that predate CPUID.1:ECX[23].
 		if (c & (1 << 5)) != 0 {
 			flags |= LZCNT
-			flags |= POPCNT
+			if caps.amdCompatible {
+				flags |= POPCNT
+			}
 		}
 		if (d & (1 << 31)) != 0 {
 			flags |= AMD3DNOW
@@ -1391,41 +3861,402 @@ func support() (Flags, AmxFlags) {
 			flags |= SSE2SLOW
 		}
 
-		/* XOP and FMA4 use the AVX instruction coding scheme, so they can't be
-		 * used unless the OS has AVX support. */
-		if (flags & AVX) != 0 {
-			if (c & 0x00000800) != 0 {
-				flags |= XOP
-			}
-			if (c & 0x00010000) != 0 {
-				flags |= FMA4
-			}
-		}
+		/* XOP and FMA4 use the AVX instruction coding scheme, so they can't be
+		 * used unless the OS has AVX support. */
+		if (flags & AVX) != 0 {
+			if (c & 0x00000800) != 0 {
+				flags |= XOP
+			}
+			if (c & 0x00010000) != 0 {
+				flags |= FMA4
+			}
+		}
+
+		if vend == Intel {
+			family, model := familyModel()
+			if family == 6 && (model == 9 || model == 13 || model == 14) {
+				/* 6/9 (pentium-m "banias"), 6/13 (pentium-m "dothan"), and
+				 * 6/14 (core1 "yonah") theoretically support sse2, but it's
+				 * usually slower than mmx. */
+				if (flags & SSE2) != 0 {
+					flags |= SSE2SLOW
+				}
+				if (flags & SSE3) != 0 {
+					flags |= SSE3SLOW
+				}
+			}
+			/* The Atom processor has SSSE3 support, which is useful in many cases,
+			 * but sometimes the SSSE3 version is slower than the SSE2 equivalent
+			 * on the Atom, but is generally faster on other processors supporting
+			 * SSSE3. This flag allows for selectively disabling certain SSSE3
+			 * functions on the Atom. */
+			if family == 6 && model == 28 {
+				flags |= ATOM
+			}
+		}
+	}
+
+	// Key Locker. AESKLE is only set when the OS has enabled Key Locker.
+	if mfi >= 0x19 && extFlags&KEYLOCKER != 0 {
+		_, b, _, _ := cpuid(0x19)
+		if b&1 != 0 {
+			extFlags |= AESKLE
+			if b&(1<<2) != 0 {
+				extFlags |= WIDEKL
+			}
+		}
+	}
+
+	// VIA/Zhaoxin PadLock.
+	if caps.centaurLeaves {
+		if mc, _, _, _ := cpuid(0xc0000000); mc >= 0xc0000001 {
+			_, _, _, d := cpuid(0xc0000001)
+			if d&(1<<2) != 0 {
+				extFlags |= PADLOCKRNG
+			}
+			if d&(1<<3) != 0 {
+				extFlags |= PADLOCKRNGEN
+			}
+			if d&(1<<6) != 0 {
+				extFlags |= PADLOCKACE
+			}
+			if d&(1<<7) != 0 {
+				extFlags |= PADLOCKACEEN
+			}
+			if d&(1<<8) != 0 {
+				extFlags |= PADLOCKACE2
+			}
+			if d&(1<<9) != 0 {
+				extFlags |= PADLOCKACE2EN
+			}
+			if d&(1<<10) != 0 {
+				extFlags |= PADLOCKPHE
+			}
+			if d&(1<<11) != 0 {
+				extFlags |= PADLOCKPHEEN
+			}
+			if d&(1<<12) != 0 {
+				extFlags |= PADLOCKPMM
+			}
+			if d&(1<<13) != 0 {
+				extFlags |= PADLOCKPMMEN
+			}
+		}
+	}
+
+	// Intel Processor Trace capabilities.
+	// Leaf 0x14 is only defined if Intel PT is present.
+	if mfi >= 0x14 {
+		if _, b7, _, _ := cpuidex(7, 0); b7&(1<<25) != 0 {
+			_, b, _, _ := cpuidex(0x14, 0)
+			if b&(1<<4) != 0 {
+				extFlags |= PTWRITE
+			}
+		}
+	}
+	return Flags(flags), amxFlags, extFlags
+}
+
+func sysSupport() SysFlags {
+	mfi := maxFunctionID()
+	if mfi < 0x1 {
+		return 0
+	}
+	var flags SysFlags
+	_, _, c, _ := cpuid(1)
+	if c&(1<<17) != 0 {
+		flags |= PCID
+	}
+	if mfi >= 7 {
+		_, ebx, ecx, edx := cpuidex(7, 0)
+		if ebx&(1<<0) != 0 {
+			flags |= FSGSBASE
+		}
+		if ebx&(1<<7) != 0 {
+			flags |= SMEP
+		}
+		if ebx&(1<<10) != 0 {
+			flags |= INVPCID
+		}
+		if ebx&(1<<20) != 0 {
+			flags |= SMAP
+		}
+		if ecx&(1<<2) != 0 {
+			flags |= UMIP
+		}
+		if ecx&(1<<3) != 0 {
+			flags |= PKU
+		}
+		// OSPKE is set when CR4.PKE is set. The OS must also manage the PKRU state, XCR0[9].
+		if ecx&(1<<4) != 0 && c&((1<<26)|(1<<27)) == (1<<26)|(1<<27) {
+			if xcr0, _ := xgetbv(0); xcr0&(1<<9) != 0 {
+				flags |= OSPKE
+			}
+		}
+		if ecx&(1<<7) != 0 {
+			flags |= CETSS
+		}
+		if ecx&(1<<13) != 0 {
+			flags |= TME
+		}
+		if ecx&(1<<16) != 0 {
+			flags |= LA57
+		}
+		if edx&(1<<20) != 0 {
+			flags |= CETIBT
+		}
+		// CET state is supervisor state, so enablement cannot be read from XCR0.
+		// Use what the OS reports instead.
+		if flags&(CETSS|CETIBT) != 0 {
+			osFlags := osCPUFlags()
+			if flags&CETSS != 0 && osFlags["user_shstk"] {
+				flags |= OSCETSS
+			}
+			// The ibt flag means the kernel itself uses IBT.
+			// Linux does not support IBT for user space.
+			if flags&CETIBT != 0 && osFlags["ibt"] {
+				flags |= OSCETIBT
+			}
+		}
+	}
+	if maxExtendedFunction() >= 0x80000001 {
+		_, _, _, d := cpuid(0x80000001)
+		if d&(1<<26) != 0 {
+			flags |= PDPE1GB
+		}
+	}
+	return flags
+}
+
+// legacySupport returns the architectural features in CPUID leaf 1.
+func legacySupport() LegacyFlags {
+	if maxFunctionID() < 0x1 {
+		return 0
+	}
+	var flags LegacyFlags
+	_, _, c, d := cpuid(1)
+	if d&(1<<0) != 0 {
+		flags |= FPU
+	}
+	if d&(1<<1) != 0 {
+		flags |= VME
+	}
+	if d&(1<<2) != 0 {
+		flags |= DE
+	}
+	if d&(1<<3) != 0 {
+		flags |= PSE
+	}
+	if d&(1<<4) != 0 {
+		flags |= TSC
+	}
+	if d&(1<<5) != 0 {
+		flags |= MSR
+	}
+	if d&(1<<6) != 0 {
+		flags |= PAE
+	}
+	if d&(1<<7) != 0 {
+		flags |= MCE
+	}
+	if d&(1<<8) != 0 {
+		flags |= CX8
+	}
+	if d&(1<<9) != 0 {
+		flags |= APIC
+	}
+	if d&(1<<11) != 0 {
+		flags |= SEP
+	}
+	if d&(1<<12) != 0 {
+		flags |= MTRR
+	}
+	if d&(1<<13) != 0 {
+		flags |= PGE
+	}
+	if d&(1<<14) != 0 {
+		flags |= MCA
+	}
+	if d&(1<<16) != 0 {
+		flags |= PAT
+	}
+	if d&(1<<17) != 0 {
+		flags |= PSE36
+	}
+	if d&(1<<19) != 0 {
+		flags |= CLFSH
+	}
+	if d&(1<<21) != 0 {
+		flags |= DS
+	}
+	if d&(1<<22) != 0 {
+		flags |= ACPI
+	}
+	if d&(1<<24) != 0 {
+		flags |= FXSR
+	}
+	if d&(1<<27) != 0 {
+		flags |= SS
+	}
+	if d&(1<<29) != 0 {
+		flags |= TM
+	}
+	if d&(1<<31) != 0 {
+		flags |= PBE
+	}
+
+	if c&(1<<2) != 0 {
+		flags |= DTES64
+	}
+	if c&(1<<3) != 0 {
+		flags |= MONITOR
+	}
+	if c&(1<<4) != 0 {
+		flags |= DSCPL
+	}
+	if c&(1<<6) != 0 {
+		flags |= SMX
+	}
+	if c&(1<<7) != 0 {
+		flags |= EST
+	}
+	if c&(1<<8) != 0 {
+		flags |= TM2
+	}
+	if c&(1<<10) != 0 {
+		flags |= CNXTID
+	}
+	if c&(1<<11) != 0 {
+		flags |= SDBG
+	}
+	if c&(1<<14) != 0 {
+		flags |= XTPR
+	}
+	if c&(1<<15) != 0 {
+		flags |= PDCM
+	}
+	if c&(1<<18) != 0 {
+		flags |= DCA
+	}
+	if c&(1<<21) != 0 {
+		flags |= X2APIC
+	}
+	if c&(1<<22) != 0 {
+		flags |= MOVBE
+	}
+	if c&(1<<24) != 0 {
+		flags |= TSCDEADLINE
+	}
+	if c&(1<<26) != 0 {
+		flags |= XSAVE
+	}
+	if c&(1<<27) != 0 {
+		flags |= OSXSAVE
+	}
+	if c&(1<<31) != 0 {
+		flags |= HYPERVISOR
+	}
+	return flags
+}
 
-		if vend == Intel {
-			family, model := familyModel()
-			if family == 6 && (model == 9 || model == 13 || model == 14) {
-				/* 6/9 (pentium-m "banias"), 6/13 (pentium-m "dothan"), and
-				 * 6/14 (core1 "yonah") theoretically support sse2, but it's
-				 * usually slower than mmx. */
-				if (flags & SSE2) != 0 {
-					flags |= SSE2SLOW
-				}
-				if (flags & SSE3) != 0 {
-					flags |= SSE3SLOW
-				}
-			}
-			/* The Atom processor has SSSE3 support, which is useful in many cases,
-			 * but sometimes the SSSE3 version is slower than the SSE2 equivalent
-			 * on the Atom, but is generally faster on other processors supporting
-			 * SSSE3. This flag allows for selectively disabling certain SSSE3
-			 * functions on the Atom. */
-			if family == 6 && model == 28 {
-				flags |= ATOM
-			}
-		}
+// amdSupport returns the extended features in CPUID 0x80000001 ECX and 0x80000008 EBX.
+func amdSupport() AmdFlags {
+	mx := maxExtendedFunction()
+	if mx < 0x80000001 {
+		return 0
+	}
+	var flags AmdFlags
+	_, _, c, _ := cpuid(0x80000001)
+	if c&(1<<0) != 0 {
+		flags |= LAHF
+	}
+	if c&(1<<1) != 0 {
+		flags |= CMPLEGACY
+	}
+	if c&(1<<2) != 0 {
+		flags |= SVM
+	}
+	if c&(1<<3) != 0 {
+		flags |= EXTAPICSPACE
+	}
+	if c&(1<<4) != 0 {
+		flags |= ALTMOVCR8
+	}
+	if c&(1<<5) != 0 {
+		flags |= ABM
+	}
+	if c&(1<<7) != 0 {
+		flags |= MISALIGNSSE
+	}
+	if c&(1<<8) != 0 {
+		flags |= PREFETCHW
+	}
+	if c&(1<<9) != 0 {
+		flags |= OSVW
+	}
+	if c&(1<<10) != 0 {
+		flags |= IBS
+	}
+	if c&(1<<12) != 0 {
+		flags |= SKINIT
 	}
-	return Flags(flags), AmxFlags(amxFlags)
+	if c&(1<<13) != 0 {
+		flags |= WDT
+	}
+	if c&(1<<15) != 0 {
+		flags |= LWP
+	}
+	if c&(1<<17) != 0 {
+		flags |= TCE
+	}
+	if c&(1<<19) != 0 {
+		flags |= NODEID
+	}
+	if c&(1<<22) != 0 {
+		flags |= TOPOEXT
+	}
+	if c&(1<<23) != 0 {
+		flags |= PERFCTREXTCORE
+	}
+	if c&(1<<24) != 0 {
+		flags |= PERFCTREXTNB
+	}
+	if c&(1<<26) != 0 {
+		flags |= DATABKPTEXT
+	}
+	if c&(1<<27) != 0 {
+		flags |= PERFTSC
+	}
+	if c&(1<<29) != 0 {
+		flags |= MONITORX
+	}
+
+	if mx < 0x80000008 {
+		return flags
+	}
+	_, b, _, _ := cpuid(0x80000008)
+	if b&(1<<0) != 0 {
+		flags |= CLZERO
+	}
+	if b&(1<<1) != 0 {
+		flags |= INSTRETCNT
+	}
+	if b&(1<<2) != 0 {
+		flags |= RSTRFPERR
+	}
+	if b&(1<<3) != 0 {
+		flags |= INVLPGB
+	}
+	if b&(1<<4) != 0 {
+		flags |= RDPRU
+	}
+	if b&(1<<8) != 0 {
+		flags |= MCOMMIT
+	}
+	if b&(1<<9) != 0 {
+		flags |= WBNOINVD
+	}
+	return flags
 }
 
 func valAsString(values ...uint32) []byte {
@@ -1450,6 +4281,344 @@ func valAsString(values ...uint32) []byte {
 	return r
 }
 
+// Linux auxiliary vector entry types.
+const (
+	atHWCAP  = 16
+	atHWCAP2 = 26
+)
+
+// hwcapCPUID is set in AT_HWCAP on Linux arm64 if the kernel emulates reading the ID registers.
+const hwcapCPUID = 1 << 11
+
+// armHWCAP maps the bits of AT_HWCAP on Linux arm64 to ArmFlags.
+var armHWCAP = [...]ArmFlags{
+	0:  FP,
+	1:  ASIMD,
+	2:  EVTSTRM,
+	3:  AES,
+	4:  PMULL,
+	5:  SHA1,
+	6:  SHA2,
+	7:  CRC32,
+	8:  ATOMICS,
+	9:  FPHP,
+	10: ASIMDHP,
+	11: ARMCPUID,
+	12: ASIMDRDM,
+	13: JSCVT,
+	14: FCMA,
+	15: LRCPC,
+	16: DCPOP,
+	17: SHA3,
+	18: SM3,
+	19: SM4,
+	20: ASIMDDP,
+	21: SHA512,
+	22: SVE,
+	23: ASIMDFHM,
+	24: DIT,
+	25: USCAT,
+	26: ILRCPC,
+	27: FLAGM,
+	28: SSBS,
+	29: SB,
+	30: PACA,
+	31: PACG,
+}
+
+// armHWCAP2 maps the bits of AT_HWCAP2 on Linux arm64 to ArmFlags.
+var armHWCAP2 = [...]ArmFlags{
+	0:  DCPODP,
+	1:  SVE2,
+	2:  SVEAES,
+	3:  SVEPMULL,
+	4:  SVEBITPERM,
+	5:  SVESHA3,
+	6:  SVESM4,
+	7:  FLAGM2,
+	8:  FRINT,
+	9:  SVEI8MM,
+	10: SVEF32MM,
+	11: SVEF64MM,
+	12: SVEBF16,
+	13: I8MM,
+	14: BF16,
+	15: DGH,
+	16: RNG,
+	17: BTI,
+	18: MTE,
+	19: ECV,
+	20: AFP,
+	21: RPRES,
+	22: MTE3,
+	31: WFXT,
+	32: EBF16,
+	33: SVEEBF16,
+	34: CSSC,
+	35: RPRFM,
+	43: MOPS,
+	44: HBC,
+}
+
+// smeHWCAP2 maps the bits of AT_HWCAP2 on Linux arm64 to SmeFlags.
+var smeHWCAP2 = [...]SmeFlags{
+	23: SME,
+	24: SMEI16I64,
+	25: SMEF64F64,
+	26: SMEI8I32,
+	27: SMEF16F32,
+	28: SMEB16F32,
+	29: SMEF32F32,
+	30: SMEFA64,
+	37: SME2,
+	38: SME2P1,
+	39: SMEI16I32,
+	40: SMEBI32I32,
+	41: SMEB16B16,
+	42: SMEF16F16,
+}
+
+// hwcapFlags returns the features in AT_HWCAP and AT_HWCAP2.
+func hwcapFlags(hwcap, hwcap2 uint64) (ArmFlags, SmeFlags) {
+	var f ArmFlags
+	var sme SmeFlags
+	for bit, flag := range armHWCAP {
+		if hwcap&(1<<uint(bit)) != 0 {
+			f |= flag
+		}
+	}
+	for bit, flag := range armHWCAP2 {
+		if hwcap2&(1<<uint(bit)) != 0 {
+			f |= flag
+		}
+	}
+	for bit, flag := range smeHWCAP2 {
+		if hwcap2&(1<<uint(bit)) != 0 {
+			sme |= flag
+		}
+	}
+	return f, sme
+}
+
+// parseAuxv returns AT_HWCAP and AT_HWCAP2 from an auxiliary vector
+// consisting of 64-bit little endian type and value pairs.
+func parseAuxv(b []byte) (hwcap, hwcap2 uint64) {
+	for ; len(b) >= 16; b = b[16:] {
+		val := binary.LittleEndian.Uint64(b[8:])
+		switch binary.LittleEndian.Uint64(b) {
+		case 0:
+			// AT_NULL ends the vector.
+			return
+		case atHWCAP:
+			hwcap = val
+		case atHWCAP2:
+			hwcap2 = val
+		}
+	}
+	return
+}
+
+// ArmID contains the fields of the arm64 Main ID Register (MIDR_EL1).
+type ArmID struct {
+	Implementer  int // Implementer code, for example 0x41 for Arm Limited
+	Variant      int // Major revision of the part
+	Architecture int // Architecture code, 0xf for architecture defined by ID registers
+	PartNum      int // Implementer defined part number
+	Revision     int // Minor revision of the part
+}
+
+// decodeMidr returns the fields of MIDR_EL1.
+func decodeMidr(midr uint64) ArmID {
+	return ArmID{
+		Implementer:  int(midr>>24) & 0xff,
+		Variant:      int(midr>>20) & 0xf,
+		Architecture: int(midr>>16) & 0xf,
+		PartNum:      int(midr>>4) & 0xfff,
+		Revision:     int(midr) & 0xf,
+	}
+}
+
+// sysfsMidr returns MIDR_EL1 of the first CPU as exported by Linux.
+// 0 is returned if it is not available.
+func sysfsMidr() uint64 {
+	s, ok := osReadString(sysfsRoot, "devices/system/cpu/cpu0/regs/identification/midr_el1")
+	if !ok {
+		return 0
+	}
+	midr, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0
+	}
+	return midr
+}
+
+// armImplementer describes an arm64 implementer code.
+type armImplementer struct {
+	vendor Vendor
+	name   string
+	parts  map[int]string
+}
+
+// armImplementers maps MIDR_EL1 implementer codes to vendors and known part numbers.
+// Ampere Altra and AWS Graviton 1 to 4 use Arm cores and report the Arm implementer
+// (Cortex-A72, Neoverse-N1, Neoverse-V1 and Neoverse-V2 respectively).
+var armImplementers = map[int]armImplementer{
+	0x41: {vendor: ARM, name: "ARM", parts: map[int]string{
+		0xd03: "Cortex-A53",
+		0xd04: "Cortex-A35",
+		0xd05: "Cortex-A55",
+		0xd07: "Cortex-A57",
+		0xd08: "Cortex-A72",
+		0xd09: "Cortex-A73",
+		0xd0a: "Cortex-A75",
+		0xd0b: "Cortex-A76",
+		0xd0c: "Neoverse-N1",
+		0xd0d: "Cortex-A77",
+		0xd40: "Neoverse-V1",
+		0xd41: "Cortex-A78",
+		0xd44: "Cortex-X1",
+		0xd46: "Cortex-A510",
+		0xd47: "Cortex-A710",
+		0xd48: "Cortex-X2",
+		0xd49: "Neoverse-N2",
+		0xd4a: "Neoverse-E1",
+		0xd4b: "Cortex-A78C",
+		0xd4d: "Cortex-A715",
+		0xd4e: "Cortex-X3",
+		0xd4f: "Neoverse-V2",
+		0xd80: "Cortex-A520",
+		0xd81: "Cortex-A720",
+		0xd82: "Cortex-X4",
+		0xd84: "Neoverse-V3",
+		0xd8e: "Neoverse-N3",
+	}},
+	0x46: {vendor: Fujitsu, name: "Fujitsu", parts: map[int]string{
+		0x001: "A64FX",
+	}},
+	0x48: {vendor: HiSilicon, name: "HiSilicon", parts: map[int]string{
+		0xd01: "Kunpeng-920", // TaiShan v110
+	}},
+	0x4e: {vendor: NVIDIA, name: "NVIDIA", parts: map[int]string{
+		0x000: "Denver",
+		0x003: "Denver 2",
+		0x004: "Carmel",
+	}},
+	0x51: {vendor: Qualcomm, name: "Qualcomm", parts: map[int]string{
+		0x001: "Oryon",
+		0x800: "Kryo 2XX Gold",
+		0x801: "Kryo 2XX Silver",
+		0x802: "Kryo 3XX Gold",
+		0x803: "Kryo 3XX Silver",
+		0x804: "Kryo 4XX Gold",
+		0x805: "Kryo 4XX Silver",
+		0xc00: "Falkor",
+	}},
+	0x61: {vendor: Apple, name: "Apple", parts: map[int]string{
+		0x022: "M1 Icestorm",
+		0x023: "M1 Firestorm",
+		0x024: "M1 Pro Icestorm",
+		0x025: "M1 Pro Firestorm",
+		0x028: "M1 Max Icestorm",
+		0x029: "M1 Max Firestorm",
+		0x032: "M2 Blizzard",
+		0x033: "M2 Avalanche",
+		0x034: "M2 Pro Blizzard",
+		0x035: "M2 Pro Avalanche",
+		0x038: "M2 Max Blizzard",
+		0x039: "M2 Max Avalanche",
+	}},
+	0xc0: {vendor: Ampere, name: "Ampere", parts: map[int]string{
+		0xac3: "AmpereOne",
+		0xac4: "AmpereOne AC04",
+	}},
+}
+
+// armIdentify fills the vendor, brand name, family and model from MIDR_EL1.
+// The family is the part number and the model is the variant and revision,
+// so r1p2 of a part is model 0x12.
+func (c *CPUInfo) armIdentify(midr uint64) {
+	if midr == 0 {
+		return
+	}
+	id := decodeMidr(midr)
+	c.ArmID = id
+	c.Family = id.PartNum
+	c.Model = id.Variant<<4 | id.Revision
+	impl, ok := armImplementers[id.Implementer]
+	if !ok {
+		c.VendorString = fmt.Sprintf("0x%02x", id.Implementer)
+		return
+	}
+	c.VendorID = impl.vendor
+	c.VendorString = impl.name
+	if part, ok := impl.parts[id.PartNum]; ok {
+		c.BrandName = impl.name + " " + part
+	}
+}
+
+// ArmVectorLength contains the arm64 SVE and SME vector lengths.
+type ArmVectorLength struct {
+	SVE          int  // SVE vector length in bytes. Will be 0 if SVE is unavailable.
+	SME          int  // SME streaming vector length in bytes. Will be 0 if SME is unavailable.
+	SVEPerThread bool // The kernel allows the SVE vector length to be changed with prctl(PR_SVE_SET_VL)
+	SMEPerThread bool // The kernel allows the SME vector length to be changed with prctl(PR_SME_SET_VL)
+}
+
+// Linux prctl options and result masks for the vector lengths.
+const (
+	prSVESetVL  = 50
+	prSVEGetVL  = 51
+	prSMESetVL  = 63
+	prSMEGetVL  = 64
+	prVLLenMask = 0xffff
+	prVLInherit = 1 << 17
+)
+
+// armVectorLength returns the vector lengths of the calling thread.
+// The kernel is asked first. If that fails and SVE is present
+// the SVE vector length is read with rdvl.
+func armVectorLength(sve, sme bool, rdvl func() uint64) ArmVectorLength {
+	var vl ArmVectorLength
+	if !sve && !sme {
+		return vl
+	}
+	// The vector length is changed for the thread while probing.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if sve {
+		var ok bool
+		vl.SVE, vl.SVEPerThread, ok = prctlVectorLength(prSVEGetVL, prSVESetVL)
+		if !ok {
+			vl.SVE = int(rdvl())
+		}
+	}
+	if sme {
+		vl.SME, vl.SMEPerThread, _ = prctlVectorLength(prSMEGetVL, prSMESetVL)
+	}
+	return vl
+}
+
+// prctlVectorLength returns the current vector length and whether it can be changed.
+// A different length is requested and the original configuration is restored.
+// The kernel picks the closest supported length, so the length is fixed
+// if the request does not change it.
+func prctlVectorLength(get, set uintptr) (vl int, changeable, ok bool) {
+	r, ok := osPrctl(get, 0)
+	if !ok {
+		return 0, false, false
+	}
+	vl = int(r & prVLLenMask)
+	probe := uintptr(16)
+	if vl == 16 {
+		probe = 256
+	}
+	if n, ok := osPrctl(set, probe); ok && int(n&prVLLenMask) != vl {
+		changeable = true
+		osPrctl(set, r&(prVLLenMask|prVLInherit))
+	}
+	return vl, changeable, true
+}
+
 // Single-precision and double-precision floating point
 func (c CPUInfo) ArmFP() bool {
 	return c.Arm&FP != 0
@@ -1564,3 +4733,269 @@ func (c CPUInfo) ArmSVE() bool {
 func (c CPUInfo) ArmGPA() bool {
 	return c.Arm&GPA != 0
 }
+
+// SIMD Floating point multiplication and addition (FMLAL/FMLSL)
+func (c CPUInfo) ArmASIMDFHM() bool {
+	return c.Arm&ASIMDFHM != 0
+}
+
+// Data Independent Timing
+func (c CPUInfo) ArmDIT() bool {
+	return c.Arm&DIT != 0
+}
+
+// Unaligned single-copy atomicity and atomic ordering
+func (c CPUInfo) ArmUSCAT() bool {
+	return c.Arm&USCAT != 0
+}
+
+// Release consistent processor consistent with immediate offset (LDAPUR, etc)
+func (c CPUInfo) ArmILRCPC() bool {
+	return c.Arm&ILRCPC != 0
+}
+
+// Condition flag manipulation (CFINV, RMIF, SETF8, SETF16)
+func (c CPUInfo) ArmFLAGM() bool {
+	return c.Arm&FLAGM != 0
+}
+
+// Speculative Store Bypass Safe control (PSTATE.SSBS)
+func (c CPUInfo) ArmSSBS() bool {
+	return c.Arm&SSBS != 0
+}
+
+// Speculation Barrier
+func (c CPUInfo) ArmSB() bool {
+	return c.Arm&SB != 0
+}
+
+// Address Pointer Authentication
+func (c CPUInfo) ArmPACA() bool {
+	return c.Arm&PACA != 0
+}
+
+// Data cache clean to Point of Deep Persistence (DC CVADP)
+func (c CPUInfo) ArmDCPODP() bool {
+	return c.Arm&DCPODP != 0
+}
+
+// Scalable Vector Extension 2
+func (c CPUInfo) ArmSVE2() bool {
+	return c.Arm&SVE2 != 0
+}
+
+// SVE AES instructions
+func (c CPUInfo) ArmSVEAES() bool {
+	return c.Arm&SVEAES != 0
+}
+
+// SVE Polynomial Multiply Long on 64-bit elements (PMULLB/PMULLT)
+func (c CPUInfo) ArmSVEPMULL() bool {
+	return c.Arm&SVEPMULL != 0
+}
+
+// SVE bit permute instructions (BDEP, BEXT, BGRP)
+func (c CPUInfo) ArmSVEBITPERM() bool {
+	return c.Arm&SVEBITPERM != 0
+}
+
+// SVE SHA-3 instructions (RAX1)
+func (c CPUInfo) ArmSVESHA3() bool {
+	return c.Arm&SVESHA3 != 0
+}
+
+// SVE SM4 instructions
+func (c CPUInfo) ArmSVESM4() bool {
+	return c.Arm&SVESM4 != 0
+}
+
+// Condition flag format conversion (AXFLAG, XAFLAG)
+func (c CPUInfo) ArmFLAGM2() bool {
+	return c.Arm&FLAGM2 != 0
+}
+
+// Round floating point to 32/64-bit integer (FRINT32Z, etc)
+func (c CPUInfo) ArmFRINT() bool {
+	return c.Arm&FRINT != 0
+}
+
+// SVE Int8 matrix multiplication
+func (c CPUInfo) ArmSVEI8MM() bool {
+	return c.Arm&SVEI8MM != 0
+}
+
+// SVE single-precision floating point matrix multiplication
+func (c CPUInfo) ArmSVEF32MM() bool {
+	return c.Arm&SVEF32MM != 0
+}
+
+// SVE double-precision floating point matrix multiplication
+func (c CPUInfo) ArmSVEF64MM() bool {
+	return c.Arm&SVEF64MM != 0
+}
+
+// SVE BFloat16 instructions
+func (c CPUInfo) ArmSVEBF16() bool {
+	return c.Arm&SVEBF16 != 0
+}
+
+// Advanced SIMD Int8 matrix multiplication
+func (c CPUInfo) ArmI8MM() bool {
+	return c.Arm&I8MM != 0
+}
+
+// Advanced SIMD BFloat16 instructions
+func (c CPUInfo) ArmBF16() bool {
+	return c.Arm&BF16 != 0
+}
+
+// Data Gathering Hint
+func (c CPUInfo) ArmDGH() bool {
+	return c.Arm&DGH != 0
+}
+
+// Random number generator (RNDR, RNDRRS)
+func (c CPUInfo) ArmRNG() bool {
+	return c.Arm&RNG != 0
+}
+
+// Branch Target Identification
+func (c CPUInfo) ArmBTI() bool {
+	return c.Arm&BTI != 0
+}
+
+// Memory Tagging Extension
+func (c CPUInfo) ArmMTE() bool {
+	return c.Arm&MTE != 0
+}
+
+// Enhanced Counter Virtualization
+func (c CPUInfo) ArmECV() bool {
+	return c.Arm&ECV != 0
+}
+
+// Alternate floating point behaviour (FPCR.AH, FIZ, NEP)
+func (c CPUInfo) ArmAFP() bool {
+	return c.Arm&AFP != 0
+}
+
+// Increased precision of reciprocal estimate and square root estimate
+func (c CPUInfo) ArmRPRES() bool {
+	return c.Arm&RPRES != 0
+}
+
+// Memory Tagging Extension with asymmetric tag check faults
+func (c CPUInfo) ArmMTE3() bool {
+	return c.Arm&MTE3 != 0
+}
+
+// WFE and WFI with timeout (WFET, WFIT)
+func (c CPUInfo) ArmWFXT() bool {
+	return c.Arm&WFXT != 0
+}
+
+// Extended BFloat16 behaviour (FPCR.EBF)
+func (c CPUInfo) ArmEBF16() bool {
+	return c.Arm&EBF16 != 0
+}
+
+// SVE extended BFloat16 behaviour
+func (c CPUInfo) ArmSVEEBF16() bool {
+	return c.Arm&SVEEBF16 != 0
+}
+
+// Common short sequence compression instructions (ABS, CNT, CTZ, SMAX, etc)
+func (c CPUInfo) ArmCSSC() bool {
+	return c.Arm&CSSC != 0
+}
+
+// Range prefetch memory hint (RPRFM)
+func (c CPUInfo) ArmRPRFM() bool {
+	return c.Arm&RPRFM != 0
+}
+
+// Memory copy and set instructions (CPYP, SETP, etc)
+func (c CPUInfo) ArmMOPS() bool {
+	return c.Arm&MOPS != 0
+}
+
+// Hinted conditional branches (BC.cond)
+func (c CPUInfo) ArmHBC() bool {
+	return c.Arm&HBC != 0
+}
+
+// Generic Pointer Authentication using any algorithm.
+// ArmGPA is only set for the architected algorithm.
+func (c CPUInfo) ArmPACG() bool {
+	return c.Arm&PACG != 0
+}
+
+// Scalable Matrix Extension
+func (c CPUInfo) ArmSME() bool {
+	return c.SmeFeatures&SME != 0
+}
+
+// SME 16-bit integer outer products into 64-bit integers
+func (c CPUInfo) ArmSMEI16I64() bool {
+	return c.SmeFeatures&SMEI16I64 != 0
+}
+
+// SME double-precision floating point outer products
+func (c CPUInfo) ArmSMEF64F64() bool {
+	return c.SmeFeatures&SMEF64F64 != 0
+}
+
+// SME 8-bit integer outer products into 32-bit integers
+func (c CPUInfo) ArmSMEI8I32() bool {
+	return c.SmeFeatures&SMEI8I32 != 0
+}
+
+// SME half-precision outer products into single-precision
+func (c CPUInfo) ArmSMEF16F32() bool {
+	return c.SmeFeatures&SMEF16F32 != 0
+}
+
+// SME BFloat16 outer products into single-precision
+func (c CPUInfo) ArmSMEB16F32() bool {
+	return c.SmeFeatures&SMEB16F32 != 0
+}
+
+// SME single-precision floating point outer products
+func (c CPUInfo) ArmSMEF32F32() bool {
+	return c.SmeFeatures&SMEF32F32 != 0
+}
+
+// Full A64 instruction set in streaming SVE mode
+func (c CPUInfo) ArmSMEFA64() bool {
+	return c.SmeFeatures&SMEFA64 != 0
+}
+
+// Scalable Matrix Extension 2
+func (c CPUInfo) ArmSME2() bool {
+	return c.SmeFeatures&SME2 != 0
+}
+
+// Scalable Matrix Extension 2.1
+func (c CPUInfo) ArmSME2P1() bool {
+	return c.SmeFeatures&SME2P1 != 0
+}
+
+// SME 16-bit integer outer products into 32-bit integers
+func (c CPUInfo) ArmSMEI16I32() bool {
+	return c.SmeFeatures&SMEI16I32 != 0
+}
+
+// SME 1-bit binary outer products into 32-bit integers
+func (c CPUInfo) ArmSMEBI32I32() bool {
+	return c.SmeFeatures&SMEBI32I32 != 0
+}
+
+// SME non-widening BFloat16 instructions
+func (c CPUInfo) ArmSMEB16B16() bool {
+	return c.SmeFeatures&SMEB16B16 != 0
+}
+
+// SME non-widening half-precision instructions
+func (c CPUInfo) ArmSMEF16F16() bool {
+	return c.SmeFeatures&SMEF16F16 != 0
+}
@@ -24,3 +24,8 @@ TEXT ·getInstAttributes(SB), 7, $0
 	MOVD R1, instAttrReg1+8(FP)
 	RET
 
+// func getVectorLength
+TEXT ·getVectorLength(SB), 7, $0
+	WORD $0x04bf5020            // rdvl x0, #1              /* SVE vector length in bytes */
+	MOVD R0, vl+0(FP)
+	RET
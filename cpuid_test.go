@@ -24,13 +24,21 @@ func TestCPUID(t *testing.T) {
 	t.Log("Family", CPU.Family, "Model:", CPU.Model)
 	t.Log("Features:", CPU.Features)
 	t.Log("ARM Features:", CPU.Arm)
+	t.Log("SME Features:", CPU.SmeFeatures)
+	t.Logf("ARM vector length: %+v", CPU.ArmVectorLength)
+	t.Logf("ARM ID: %+v", CPU.ArmID)
 	t.Log("AMX Features:", CPU.AmxFeatures)
+	t.Log("Extended Features:", CPU.ExtFeatures)
+	t.Log("System Features:", CPU.SysFeatures)
+	t.Log("Legacy Features:", CPU.LegacyFeatures)
 	t.Log("Cacheline bytes:", CPU.CacheLine)
 	t.Log("L1 Instruction Cache:", CPU.Cache.L1I, "bytes")
 	t.Log("L1 Data Cache:", CPU.Cache.L1D, "bytes")
 	t.Log("L2 Cache:", CPU.Cache.L2, "bytes")
 	t.Log("L3 Cache:", CPU.Cache.L3, "bytes")
+	t.Log("Cache associativity (L1I, L1D, L2, L3):", CPU.Cache.L1IWays, CPU.Cache.L1DWays, CPU.Cache.L2Ways, CPU.Cache.L3Ways)
 	t.Log("Hz:", CPU.Hz, "Hz")
+	t.Logf("PMU: %+v", CPU.PMU)
 
 	if CPU.SSE2() {
 		t.Log("We have SSE2")
@@ -713,6 +721,1316 @@ func TestAMXINT8(t *testing.T) {
 	t.Log("AMXINT8 Support:", got)
 }
 
+// TestAVXVNNI tests AVXVNNI() function (AVX (VEX encoded) VNNI neural network instructions)
+func TestAVXVNNI(t *testing.T) {
+	got := CPU.AVXVNNI()
+	expected := CPU.ExtFeatures&AVXVNNI == AVXVNNI
+	if got != expected {
+		t.Fatalf("AVXVNNI: expected %v, got %v", expected, got)
+	}
+	t.Log("AVXVNNI Support:", got)
+}
+
+// TestAVXIFMA tests AVXIFMA() function (AVX (VEX encoded) Integer Fused Multiply-Add)
+func TestAVXIFMA(t *testing.T) {
+	got := CPU.AVXIFMA()
+	expected := CPU.ExtFeatures&AVXIFMA == AVXIFMA
+	if got != expected {
+		t.Fatalf("AVXIFMA: expected %v, got %v", expected, got)
+	}
+	t.Log("AVXIFMA Support:", got)
+}
+
+// TestAVXVNNIINT8 tests AVXVNNIINT8() function (AVX (VEX encoded) VNNI with 8-bit integers)
+func TestAVXVNNIINT8(t *testing.T) {
+	got := CPU.AVXVNNIINT8()
+	expected := CPU.ExtFeatures&AVXVNNIINT8 == AVXVNNIINT8
+	if got != expected {
+		t.Fatalf("AVXVNNIINT8: expected %v, got %v", expected, got)
+	}
+	t.Log("AVXVNNIINT8 Support:", got)
+}
+
+// TestAVXVNNIINT16 tests AVXVNNIINT16() function (AVX (VEX encoded) VNNI with 16-bit integers)
+func TestAVXVNNIINT16(t *testing.T) {
+	got := CPU.AVXVNNIINT16()
+	expected := CPU.ExtFeatures&AVXVNNIINT16 == AVXVNNIINT16
+	if got != expected {
+		t.Fatalf("AVXVNNIINT16: expected %v, got %v", expected, got)
+	}
+	t.Log("AVXVNNIINT16 Support:", got)
+}
+
+// TestAVXNECONVERT tests AVXNECONVERT() function (AVX (VEX encoded) BF16/FP16 conversion without exceptions)
+func TestAVXNECONVERT(t *testing.T) {
+	got := CPU.AVXNECONVERT()
+	expected := CPU.ExtFeatures&AVXNECONVERT == AVXNECONVERT
+	if got != expected {
+		t.Fatalf("AVXNECONVERT: expected %v, got %v", expected, got)
+	}
+	t.Log("AVXNECONVERT Support:", got)
+}
+
+// TestAVX512FP16 tests AVX512FP16() function (AVX-512 FP16 Instructions)
+func TestAVX512FP16(t *testing.T) {
+	got := CPU.AVX512FP16()
+	expected := CPU.ExtFeatures&AVX512FP16 == AVX512FP16
+	if got != expected {
+		t.Fatalf("AVX512FP16: expected %v, got %v", expected, got)
+	}
+	t.Log("AVX512FP16 Support:", got)
+}
+
+// TestCMPCCXADD tests CMPCCXADD() function (CMPccXADD instructions)
+func TestCMPCCXADD(t *testing.T) {
+	got := CPU.CMPCCXADD()
+	expected := CPU.ExtFeatures&CMPCCXADD == CMPCCXADD
+	if got != expected {
+		t.Fatalf("CMPCCXADD: expected %v, got %v", expected, got)
+	}
+	t.Log("CMPCCXADD Support:", got)
+}
+
+// TestFZLRM tests FZLRM() function (Fast Zero-Length REP MOVSB)
+func TestFZLRM(t *testing.T) {
+	got := CPU.FZLRM()
+	expected := CPU.ExtFeatures&FZLRM == FZLRM
+	if got != expected {
+		t.Fatalf("FZLRM: expected %v, got %v", expected, got)
+	}
+	t.Log("FZLRM Support:", got)
+}
+
+// TestFSRS tests FSRS() function (Fast Short REP STOSB)
+func TestFSRS(t *testing.T) {
+	got := CPU.FSRS()
+	expected := CPU.ExtFeatures&FSRS == FSRS
+	if got != expected {
+		t.Fatalf("FSRS: expected %v, got %v", expected, got)
+	}
+	t.Log("FSRS Support:", got)
+}
+
+// TestFSRCS tests FSRCS() function (Fast Short REP CMPSB/SCASB)
+func TestFSRCS(t *testing.T) {
+	got := CPU.FSRCS()
+	expected := CPU.ExtFeatures&FSRCS == FSRCS
+	if got != expected {
+		t.Fatalf("FSRCS: expected %v, got %v", expected, got)
+	}
+	t.Log("FSRCS Support:", got)
+}
+
+// TestFSRM tests FSRM() function (Fast Short REP MOVSB)
+func TestFSRM(t *testing.T) {
+	got := CPU.FSRM()
+	expected := CPU.ExtFeatures&FSRM == FSRM
+	if got != expected {
+		t.Fatalf("FSRM: expected %v, got %v", expected, got)
+	}
+	t.Log("FSRM Support:", got)
+}
+
+// TestCLFLUSHOPT tests CLFLUSHOPT() function (CLFLUSHOPT instruction)
+func TestCLFLUSHOPT(t *testing.T) {
+	got := CPU.CLFLUSHOPT()
+	expected := CPU.ExtFeatures&CLFLUSHOPT == CLFLUSHOPT
+	if got != expected {
+		t.Fatalf("CLFLUSHOPT: expected %v, got %v", expected, got)
+	}
+	t.Log("CLFLUSHOPT Support:", got)
+}
+
+// TestCLWB tests CLWB() function (Cache Line Write Back)
+func TestCLWB(t *testing.T) {
+	got := CPU.CLWB()
+	expected := CPU.ExtFeatures&CLWB == CLWB
+	if got != expected {
+		t.Fatalf("CLWB: expected %v, got %v", expected, got)
+	}
+	t.Log("CLWB Support:", got)
+}
+
+// TestCLDEMOTE tests CLDEMOTE() function (Cache Line Demote)
+func TestCLDEMOTE(t *testing.T) {
+	got := CPU.CLDEMOTE()
+	expected := CPU.ExtFeatures&CLDEMOTE == CLDEMOTE
+	if got != expected {
+		t.Fatalf("CLDEMOTE: expected %v, got %v", expected, got)
+	}
+	t.Log("CLDEMOTE Support:", got)
+}
+
+// TestMOVDIRI tests MOVDIRI() function (Move Doubleword as Direct Store)
+func TestMOVDIRI(t *testing.T) {
+	got := CPU.MOVDIRI()
+	expected := CPU.ExtFeatures&MOVDIRI == MOVDIRI
+	if got != expected {
+		t.Fatalf("MOVDIRI: expected %v, got %v", expected, got)
+	}
+	t.Log("MOVDIRI Support:", got)
+}
+
+// TestMOVDIR64B tests MOVDIR64B() function (Move 64 Bytes as Direct Store)
+func TestMOVDIR64B(t *testing.T) {
+	got := CPU.MOVDIR64B()
+	expected := CPU.ExtFeatures&MOVDIR64B == MOVDIR64B
+	if got != expected {
+		t.Fatalf("MOVDIR64B: expected %v, got %v", expected, got)
+	}
+	t.Log("MOVDIR64B Support:", got)
+}
+
+// TestENQCMD tests ENQCMD() function (Enqueue Command)
+func TestENQCMD(t *testing.T) {
+	got := CPU.ENQCMD()
+	expected := CPU.ExtFeatures&ENQCMD == ENQCMD
+	if got != expected {
+		t.Fatalf("ENQCMD: expected %v, got %v", expected, got)
+	}
+	t.Log("ENQCMD Support:", got)
+}
+
+// TestPREFETCHWT1 tests PREFETCHWT1() function (PREFETCHWT1 instruction)
+func TestPREFETCHWT1(t *testing.T) {
+	got := CPU.PREFETCHWT1()
+	expected := CPU.ExtFeatures&PREFETCHWT1 == PREFETCHWT1
+	if got != expected {
+		t.Fatalf("PREFETCHWT1: expected %v, got %v", expected, got)
+	}
+	t.Log("PREFETCHWT1 Support:", got)
+}
+
+// TestSERIALIZE tests SERIALIZE() function (SERIALIZE instruction)
+func TestSERIALIZE(t *testing.T) {
+	got := CPU.SERIALIZE()
+	expected := CPU.ExtFeatures&SERIALIZE == SERIALIZE
+	if got != expected {
+		t.Fatalf("SERIALIZE: expected %v, got %v", expected, got)
+	}
+	t.Log("SERIALIZE Support:", got)
+}
+
+// TestTSXLDTRK tests TSXLDTRK() function (Intel TSX Suspend Load Address Tracking)
+func TestTSXLDTRK(t *testing.T) {
+	got := CPU.TSXLDTRK()
+	expected := CPU.ExtFeatures&TSXLDTRK == TSXLDTRK
+	if got != expected {
+		t.Fatalf("TSXLDTRK: expected %v, got %v", expected, got)
+	}
+	t.Log("TSXLDTRK Support:", got)
+}
+
+// TestWAITPKG tests WAITPKG() function (TPAUSE, UMONITOR and UMWAIT instructions)
+func TestWAITPKG(t *testing.T) {
+	got := CPU.WAITPKG()
+	expected := CPU.ExtFeatures&WAITPKG == WAITPKG
+	if got != expected {
+		t.Fatalf("WAITPKG: expected %v, got %v", expected, got)
+	}
+	t.Log("WAITPKG Support:", got)
+}
+
+// TestUINTR tests UINTR() function (User Interrupts)
+func TestUINTR(t *testing.T) {
+	got := CPU.UINTR()
+	expected := CPU.ExtFeatures&UINTR == UINTR
+	if got != expected {
+		t.Fatalf("UINTR: expected %v, got %v", expected, got)
+	}
+	t.Log("UINTR Support:", got)
+}
+
+// TestPCONFIG tests PCONFIG() function (Platform Configuration)
+func TestPCONFIG(t *testing.T) {
+	got := CPU.PCONFIG()
+	expected := CPU.ExtFeatures&PCONFIG == PCONFIG
+	if got != expected {
+		t.Fatalf("PCONFIG: expected %v, got %v", expected, got)
+	}
+	t.Log("PCONFIG Support:", got)
+}
+
+// TestPTWRITE tests PTWRITE() function (PTWRITE instruction)
+func TestPTWRITE(t *testing.T) {
+	got := CPU.PTWRITE()
+	expected := CPU.ExtFeatures&PTWRITE == PTWRITE
+	if got != expected {
+		t.Fatalf("PTWRITE: expected %v, got %v", expected, got)
+	}
+	t.Log("PTWRITE Support:", got)
+}
+
+// TestKEYLOCKER tests KEYLOCKER() function (Intel Key Locker)
+func TestKEYLOCKER(t *testing.T) {
+	got := CPU.KEYLOCKER()
+	expected := CPU.ExtFeatures&KEYLOCKER == KEYLOCKER
+	if got != expected {
+		t.Fatalf("KEYLOCKER: expected %v, got %v", expected, got)
+	}
+	t.Log("KEYLOCKER Support:", got)
+}
+
+// TestAESKLE tests AESKLE() function (AES Key Locker instructions, enabled by the OS)
+func TestAESKLE(t *testing.T) {
+	got := CPU.AESKLE()
+	expected := CPU.ExtFeatures&AESKLE == AESKLE
+	if got != expected {
+		t.Fatalf("AESKLE: expected %v, got %v", expected, got)
+	}
+	t.Log("AESKLE Support:", got)
+}
+
+// TestWIDEKL tests WIDEKL() function (AES wide Key Locker instructions, enabled by the OS)
+func TestWIDEKL(t *testing.T) {
+	got := CPU.WIDEKL()
+	expected := CPU.ExtFeatures&WIDEKL == WIDEKL
+	if got != expected {
+		t.Fatalf("WIDEKL: expected %v, got %v", expected, got)
+	}
+	t.Log("WIDEKL Support:", got)
+}
+
+// TestPADLOCKRNG tests PADLOCKRNG() function (VIA PadLock random number generator)
+func TestPADLOCKRNG(t *testing.T) {
+	got := CPU.PADLOCKRNG()
+	expected := CPU.ExtFeatures&PADLOCKRNG == PADLOCKRNG
+	if got != expected {
+		t.Fatalf("PADLOCKRNG: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKRNG Support:", got)
+}
+
+// TestPADLOCKRNGEN tests PADLOCKRNGEN() function (VIA PadLock random number generator enabled)
+func TestPADLOCKRNGEN(t *testing.T) {
+	got := CPU.PADLOCKRNGEN()
+	expected := CPU.ExtFeatures&PADLOCKRNGEN == PADLOCKRNGEN
+	if got != expected {
+		t.Fatalf("PADLOCKRNGEN: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKRNGEN Support:", got)
+}
+
+// TestPADLOCKACE tests PADLOCKACE() function (VIA PadLock Advanced Cryptography Engine)
+func TestPADLOCKACE(t *testing.T) {
+	got := CPU.PADLOCKACE()
+	expected := CPU.ExtFeatures&PADLOCKACE == PADLOCKACE
+	if got != expected {
+		t.Fatalf("PADLOCKACE: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKACE Support:", got)
+}
+
+// TestPADLOCKACEEN tests PADLOCKACEEN() function (VIA PadLock Advanced Cryptography Engine enabled)
+func TestPADLOCKACEEN(t *testing.T) {
+	got := CPU.PADLOCKACEEN()
+	expected := CPU.ExtFeatures&PADLOCKACEEN == PADLOCKACEEN
+	if got != expected {
+		t.Fatalf("PADLOCKACEEN: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKACEEN Support:", got)
+}
+
+// TestPADLOCKACE2 tests PADLOCKACE2() function (VIA PadLock Advanced Cryptography Engine 2)
+func TestPADLOCKACE2(t *testing.T) {
+	got := CPU.PADLOCKACE2()
+	expected := CPU.ExtFeatures&PADLOCKACE2 == PADLOCKACE2
+	if got != expected {
+		t.Fatalf("PADLOCKACE2: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKACE2 Support:", got)
+}
+
+// TestPADLOCKACE2EN tests PADLOCKACE2EN() function (VIA PadLock Advanced Cryptography Engine 2 enabled)
+func TestPADLOCKACE2EN(t *testing.T) {
+	got := CPU.PADLOCKACE2EN()
+	expected := CPU.ExtFeatures&PADLOCKACE2EN == PADLOCKACE2EN
+	if got != expected {
+		t.Fatalf("PADLOCKACE2EN: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKACE2EN Support:", got)
+}
+
+// TestPADLOCKPHE tests PADLOCKPHE() function (VIA PadLock Hash Engine)
+func TestPADLOCKPHE(t *testing.T) {
+	got := CPU.PADLOCKPHE()
+	expected := CPU.ExtFeatures&PADLOCKPHE == PADLOCKPHE
+	if got != expected {
+		t.Fatalf("PADLOCKPHE: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKPHE Support:", got)
+}
+
+// TestPADLOCKPHEEN tests PADLOCKPHEEN() function (VIA PadLock Hash Engine enabled)
+func TestPADLOCKPHEEN(t *testing.T) {
+	got := CPU.PADLOCKPHEEN()
+	expected := CPU.ExtFeatures&PADLOCKPHEEN == PADLOCKPHEEN
+	if got != expected {
+		t.Fatalf("PADLOCKPHEEN: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKPHEEN Support:", got)
+}
+
+// TestPADLOCKPMM tests PADLOCKPMM() function (VIA PadLock Montgomery Multiplier)
+func TestPADLOCKPMM(t *testing.T) {
+	got := CPU.PADLOCKPMM()
+	expected := CPU.ExtFeatures&PADLOCKPMM == PADLOCKPMM
+	if got != expected {
+		t.Fatalf("PADLOCKPMM: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKPMM Support:", got)
+}
+
+// TestPADLOCKPMMEN tests PADLOCKPMMEN() function (VIA PadLock Montgomery Multiplier enabled)
+func TestPADLOCKPMMEN(t *testing.T) {
+	got := CPU.PADLOCKPMMEN()
+	expected := CPU.ExtFeatures&PADLOCKPMMEN == PADLOCKPMMEN
+	if got != expected {
+		t.Fatalf("PADLOCKPMMEN: expected %v, got %v", expected, got)
+	}
+	t.Log("PADLOCKPMMEN Support:", got)
+}
+
+// TestHRESET tests HRESET() function (History reset)
+func TestHRESET(t *testing.T) {
+	got := CPU.HRESET()
+	expected := CPU.ExtFeatures&HRESET == HRESET
+	if got != expected {
+		t.Fatalf("HRESET: expected %v, got %v", expected, got)
+	}
+	t.Log("HRESET Support:", got)
+}
+
+// TestLAM tests LAM() function (Linear Address Masking)
+func TestLAM(t *testing.T) {
+	got := CPU.LAM()
+	expected := CPU.ExtFeatures&LAM == LAM
+	if got != expected {
+		t.Fatalf("LAM: expected %v, got %v", expected, got)
+	}
+	t.Log("LAM Support:", got)
+}
+
+// TestWRMSRNS tests WRMSRNS() function (Non-Serializing Write to Model Specific Register)
+func TestWRMSRNS(t *testing.T) {
+	got := CPU.WRMSRNS()
+	expected := CPU.ExtFeatures&WRMSRNS == WRMSRNS
+	if got != expected {
+		t.Fatalf("WRMSRNS: expected %v, got %v", expected, got)
+	}
+	t.Log("WRMSRNS Support:", got)
+}
+
+// TestMSRLIST tests MSRLIST() function (Read/Write List of Model Specific Registers)
+func TestMSRLIST(t *testing.T) {
+	got := CPU.MSRLIST()
+	expected := CPU.ExtFeatures&MSRLIST == MSRLIST
+	if got != expected {
+		t.Fatalf("MSRLIST: expected %v, got %v", expected, got)
+	}
+	t.Log("MSRLIST Support:", got)
+}
+
+// TestPREFETCHI tests PREFETCHI() function (PREFETCHIT0/1 instruction prefetch)
+func TestPREFETCHI(t *testing.T) {
+	got := CPU.PREFETCHI()
+	expected := CPU.ExtFeatures&PREFETCHI == PREFETCHI
+	if got != expected {
+		t.Fatalf("PREFETCHI: expected %v, got %v", expected, got)
+	}
+	t.Log("PREFETCHI Support:", got)
+}
+
+// TestSHA512 tests SHA512() function (SHA-512 instructions)
+func TestSHA512(t *testing.T) {
+	got := CPU.SHA512()
+	expected := CPU.ExtFeatures&SHA512X86 == SHA512X86
+	if got != expected {
+		t.Fatalf("SHA512: expected %v, got %v", expected, got)
+	}
+	t.Log("SHA512 Support:", got)
+}
+
+// TestSM3 tests SM3() function (SM3 hash instructions)
+func TestSM3(t *testing.T) {
+	got := CPU.SM3()
+	expected := CPU.ExtFeatures&SM3X86 == SM3X86
+	if got != expected {
+		t.Fatalf("SM3: expected %v, got %v", expected, got)
+	}
+	t.Log("SM3 Support:", got)
+}
+
+// TestSM4 tests SM4() function (SM4 block cipher instructions)
+func TestSM4(t *testing.T) {
+	got := CPU.SM4()
+	expected := CPU.ExtFeatures&SM4X86 == SM4X86
+	if got != expected {
+		t.Fatalf("SM4: expected %v, got %v", expected, got)
+	}
+	t.Log("SM4 Support:", got)
+}
+
+// TestRAOINT tests RAOINT() function (Remote Atomic Operations on integers)
+func TestRAOINT(t *testing.T) {
+	got := CPU.RAOINT()
+	expected := CPU.ExtFeatures&RAOINT == RAOINT
+	if got != expected {
+		t.Fatalf("RAOINT: expected %v, got %v", expected, got)
+	}
+	t.Log("RAOINT Support:", got)
+}
+
+// TestAVX10 tests AVX10() function (AVX10 converged vector ISA)
+func TestAVX10(t *testing.T) {
+	got := CPU.AVX10()
+	expected := CPU.ExtFeatures&AVX10 == AVX10
+	if got != expected {
+		t.Fatalf("AVX10: expected %v, got %v", expected, got)
+	}
+	t.Log("AVX10 Support:", got)
+}
+
+// TestAPX tests APX() function (Advanced Performance Extensions (APX_F))
+func TestAPX(t *testing.T) {
+	got := CPU.APX()
+	expected := CPU.ExtFeatures&APX == APX
+	if got != expected {
+		t.Fatalf("APX: expected %v, got %v", expected, got)
+	}
+	t.Log("APX Support:", got)
+}
+
+// TestPCID tests PCID() function (Process-Context Identifiers)
+func TestPCID(t *testing.T) {
+	got := CPU.PCID()
+	expected := CPU.SysFeatures&PCID == PCID
+	if got != expected {
+		t.Fatalf("PCID: expected %v, got %v", expected, got)
+	}
+	t.Log("PCID Support:", got)
+}
+
+// TestINVPCID tests INVPCID() function (INVPCID instruction)
+func TestINVPCID(t *testing.T) {
+	got := CPU.INVPCID()
+	expected := CPU.SysFeatures&INVPCID == INVPCID
+	if got != expected {
+		t.Fatalf("INVPCID: expected %v, got %v", expected, got)
+	}
+	t.Log("INVPCID Support:", got)
+}
+
+// TestFSGSBASE tests FSGSBASE() function (RDFSBASE/RDGSBASE/WRFSBASE/WRGSBASE instructions)
+func TestFSGSBASE(t *testing.T) {
+	got := CPU.FSGSBASE()
+	expected := CPU.SysFeatures&FSGSBASE == FSGSBASE
+	if got != expected {
+		t.Fatalf("FSGSBASE: expected %v, got %v", expected, got)
+	}
+	t.Log("FSGSBASE Support:", got)
+}
+
+// TestSMEP tests SMEP() function (Supervisor-Mode Execution Prevention)
+func TestSMEP(t *testing.T) {
+	got := CPU.SMEP()
+	expected := CPU.SysFeatures&SMEP == SMEP
+	if got != expected {
+		t.Fatalf("SMEP: expected %v, got %v", expected, got)
+	}
+	t.Log("SMEP Support:", got)
+}
+
+// TestSMAP tests SMAP() function (Supervisor-Mode Access Prevention)
+func TestSMAP(t *testing.T) {
+	got := CPU.SMAP()
+	expected := CPU.SysFeatures&SMAP == SMAP
+	if got != expected {
+		t.Fatalf("SMAP: expected %v, got %v", expected, got)
+	}
+	t.Log("SMAP Support:", got)
+}
+
+// TestUMIP tests UMIP() function (User-Mode Instruction Prevention)
+func TestUMIP(t *testing.T) {
+	got := CPU.UMIP()
+	expected := CPU.SysFeatures&UMIP == UMIP
+	if got != expected {
+		t.Fatalf("UMIP: expected %v, got %v", expected, got)
+	}
+	t.Log("UMIP Support:", got)
+}
+
+// TestPKU tests PKU() function (Protection Keys for User-mode pages)
+func TestPKU(t *testing.T) {
+	got := CPU.PKU()
+	expected := CPU.SysFeatures&PKU == PKU
+	if got != expected {
+		t.Fatalf("PKU: expected %v, got %v", expected, got)
+	}
+	t.Log("PKU Support:", got)
+}
+
+// TestOSPKE tests OSPKE() function (Protection Keys enabled by the OS, with PKRU state in XCR0)
+func TestOSPKE(t *testing.T) {
+	got := CPU.OSPKE()
+	expected := CPU.SysFeatures&OSPKE == OSPKE
+	if got != expected {
+		t.Fatalf("OSPKE: expected %v, got %v", expected, got)
+	}
+	t.Log("OSPKE Support:", got)
+}
+
+// TestLA57 tests LA57() function (5-level paging (57-bit linear addresses))
+func TestLA57(t *testing.T) {
+	got := CPU.LA57()
+	expected := CPU.SysFeatures&LA57 == LA57
+	if got != expected {
+		t.Fatalf("LA57: expected %v, got %v", expected, got)
+	}
+	t.Log("LA57 Support:", got)
+}
+
+// TestPDPE1GB tests PDPE1GB() function (1 GB pages)
+func TestPDPE1GB(t *testing.T) {
+	got := CPU.PDPE1GB()
+	expected := CPU.SysFeatures&PDPE1GB == PDPE1GB
+	if got != expected {
+		t.Fatalf("PDPE1GB: expected %v, got %v", expected, got)
+	}
+	t.Log("PDPE1GB Support:", got)
+}
+
+// TestCETSS tests CETSS() function (CET Shadow Stack)
+func TestCETSS(t *testing.T) {
+	got := CPU.CETSS()
+	expected := CPU.SysFeatures&CETSS == CETSS
+	if got != expected {
+		t.Fatalf("CETSS: expected %v, got %v", expected, got)
+	}
+	t.Log("CETSS Support:", got)
+}
+
+// TestCETIBT tests CETIBT() function (CET Indirect Branch Tracking)
+func TestCETIBT(t *testing.T) {
+	got := CPU.CETIBT()
+	expected := CPU.SysFeatures&CETIBT == CETIBT
+	if got != expected {
+		t.Fatalf("CETIBT: expected %v, got %v", expected, got)
+	}
+	t.Log("CETIBT Support:", got)
+}
+
+// TestOSCETSS tests OSCETSS() function (CET Shadow Stack enabled by the OS for user mode (Linux only))
+func TestOSCETSS(t *testing.T) {
+	got := CPU.OSCETSS()
+	expected := CPU.SysFeatures&OSCETSS == OSCETSS
+	if got != expected {
+		t.Fatalf("OSCETSS: expected %v, got %v", expected, got)
+	}
+	t.Log("OSCETSS Support:", got)
+}
+
+// TestOSCETIBT tests OSCETIBT() function (CET Indirect Branch Tracking enabled for the kernel (Linux only, not usable from user space))
+func TestOSCETIBT(t *testing.T) {
+	got := CPU.OSCETIBT()
+	expected := CPU.SysFeatures&OSCETIBT == OSCETIBT
+	if got != expected {
+		t.Fatalf("OSCETIBT: expected %v, got %v", expected, got)
+	}
+	t.Log("OSCETIBT Support:", got)
+}
+
+// TestTME tests TME() function (Total Memory Encryption)
+func TestTME(t *testing.T) {
+	got := CPU.TME()
+	expected := CPU.SysFeatures&TME == TME
+	if got != expected {
+		t.Fatalf("TME: expected %v, got %v", expected, got)
+	}
+	t.Log("TME Support:", got)
+}
+
+// TestFPU tests FPU() function (x87 FPU on chip)
+func TestFPU(t *testing.T) {
+	got := CPU.FPU()
+	expected := CPU.LegacyFeatures&FPU == FPU
+	if got != expected {
+		t.Fatalf("FPU: expected %v, got %v", expected, got)
+	}
+	t.Log("FPU Support:", got)
+}
+
+// TestVME tests VME() function (Virtual-8086 Mode Enhancement)
+func TestVME(t *testing.T) {
+	got := CPU.VME()
+	expected := CPU.LegacyFeatures&VME == VME
+	if got != expected {
+		t.Fatalf("VME: expected %v, got %v", expected, got)
+	}
+	t.Log("VME Support:", got)
+}
+
+// TestDE tests DE() function (Debugging Extensions)
+func TestDE(t *testing.T) {
+	got := CPU.DE()
+	expected := CPU.LegacyFeatures&DE == DE
+	if got != expected {
+		t.Fatalf("DE: expected %v, got %v", expected, got)
+	}
+	t.Log("DE Support:", got)
+}
+
+// TestPSE tests PSE() function (Page Size Extensions)
+func TestPSE(t *testing.T) {
+	got := CPU.PSE()
+	expected := CPU.LegacyFeatures&PSE == PSE
+	if got != expected {
+		t.Fatalf("PSE: expected %v, got %v", expected, got)
+	}
+	t.Log("PSE Support:", got)
+}
+
+// TestTSC tests TSC() function (Time Stamp Counter)
+func TestTSC(t *testing.T) {
+	got := CPU.TSC()
+	expected := CPU.LegacyFeatures&TSC == TSC
+	if got != expected {
+		t.Fatalf("TSC: expected %v, got %v", expected, got)
+	}
+	t.Log("TSC Support:", got)
+}
+
+// TestMSR tests MSR() function (RDMSR and WRMSR instructions)
+func TestMSR(t *testing.T) {
+	got := CPU.MSR()
+	expected := CPU.LegacyFeatures&MSR == MSR
+	if got != expected {
+		t.Fatalf("MSR: expected %v, got %v", expected, got)
+	}
+	t.Log("MSR Support:", got)
+}
+
+// TestPAE tests PAE() function (Physical Address Extensions)
+func TestPAE(t *testing.T) {
+	got := CPU.PAE()
+	expected := CPU.LegacyFeatures&PAE == PAE
+	if got != expected {
+		t.Fatalf("PAE: expected %v, got %v", expected, got)
+	}
+	t.Log("PAE Support:", got)
+}
+
+// TestMCE tests MCE() function (Machine Check Exception)
+func TestMCE(t *testing.T) {
+	got := CPU.MCE()
+	expected := CPU.LegacyFeatures&MCE == MCE
+	if got != expected {
+		t.Fatalf("MCE: expected %v, got %v", expected, got)
+	}
+	t.Log("MCE Support:", got)
+}
+
+// TestCX8 tests CX8() function (CMPXCHG8B instruction)
+func TestCX8(t *testing.T) {
+	got := CPU.CX8()
+	expected := CPU.LegacyFeatures&CX8 == CX8
+	if got != expected {
+		t.Fatalf("CX8: expected %v, got %v", expected, got)
+	}
+	t.Log("CX8 Support:", got)
+}
+
+// TestAPIC tests APIC() function (APIC on chip)
+func TestAPIC(t *testing.T) {
+	got := CPU.APIC()
+	expected := CPU.LegacyFeatures&APIC == APIC
+	if got != expected {
+		t.Fatalf("APIC: expected %v, got %v", expected, got)
+	}
+	t.Log("APIC Support:", got)
+}
+
+// TestSEP tests SEP() function (SYSENTER and SYSEXIT instructions)
+func TestSEP(t *testing.T) {
+	got := CPU.SEP()
+	expected := CPU.LegacyFeatures&SEP == SEP
+	if got != expected {
+		t.Fatalf("SEP: expected %v, got %v", expected, got)
+	}
+	t.Log("SEP Support:", got)
+}
+
+// TestMTRR tests MTRR() function (Memory Type Range Registers)
+func TestMTRR(t *testing.T) {
+	got := CPU.MTRR()
+	expected := CPU.LegacyFeatures&MTRR == MTRR
+	if got != expected {
+		t.Fatalf("MTRR: expected %v, got %v", expected, got)
+	}
+	t.Log("MTRR Support:", got)
+}
+
+// TestPGE tests PGE() function (Page Global Bit)
+func TestPGE(t *testing.T) {
+	got := CPU.PGE()
+	expected := CPU.LegacyFeatures&PGE == PGE
+	if got != expected {
+		t.Fatalf("PGE: expected %v, got %v", expected, got)
+	}
+	t.Log("PGE Support:", got)
+}
+
+// TestMCA tests MCA() function (Machine Check Architecture)
+func TestMCA(t *testing.T) {
+	got := CPU.MCA()
+	expected := CPU.LegacyFeatures&MCA == MCA
+	if got != expected {
+		t.Fatalf("MCA: expected %v, got %v", expected, got)
+	}
+	t.Log("MCA Support:", got)
+}
+
+// TestPAT tests PAT() function (Page Attribute Table)
+func TestPAT(t *testing.T) {
+	got := CPU.PAT()
+	expected := CPU.LegacyFeatures&PAT == PAT
+	if got != expected {
+		t.Fatalf("PAT: expected %v, got %v", expected, got)
+	}
+	t.Log("PAT Support:", got)
+}
+
+// TestPSE36 tests PSE36() function (36-bit Page Size Extension)
+func TestPSE36(t *testing.T) {
+	got := CPU.PSE36()
+	expected := CPU.LegacyFeatures&PSE36 == PSE36
+	if got != expected {
+		t.Fatalf("PSE36: expected %v, got %v", expected, got)
+	}
+	t.Log("PSE36 Support:", got)
+}
+
+// TestCLFSH tests CLFSH() function (CLFLUSH instruction)
+func TestCLFSH(t *testing.T) {
+	got := CPU.CLFSH()
+	expected := CPU.LegacyFeatures&CLFSH == CLFSH
+	if got != expected {
+		t.Fatalf("CLFSH: expected %v, got %v", expected, got)
+	}
+	t.Log("CLFSH Support:", got)
+}
+
+// TestDS tests DS() function (Debug Store)
+func TestDS(t *testing.T) {
+	got := CPU.DS()
+	expected := CPU.LegacyFeatures&DS == DS
+	if got != expected {
+		t.Fatalf("DS: expected %v, got %v", expected, got)
+	}
+	t.Log("DS Support:", got)
+}
+
+// TestACPI tests ACPI() function (Thermal Monitor and Software Controlled Clock Facilities)
+func TestACPI(t *testing.T) {
+	got := CPU.ACPI()
+	expected := CPU.LegacyFeatures&ACPI == ACPI
+	if got != expected {
+		t.Fatalf("ACPI: expected %v, got %v", expected, got)
+	}
+	t.Log("ACPI Support:", got)
+}
+
+// TestFXSR tests FXSR() function (FXSAVE and FXRSTOR instructions)
+func TestFXSR(t *testing.T) {
+	got := CPU.FXSR()
+	expected := CPU.LegacyFeatures&FXSR == FXSR
+	if got != expected {
+		t.Fatalf("FXSR: expected %v, got %v", expected, got)
+	}
+	t.Log("FXSR Support:", got)
+}
+
+// TestSS tests SS() function (Self Snoop)
+func TestSS(t *testing.T) {
+	got := CPU.SS()
+	expected := CPU.LegacyFeatures&SS == SS
+	if got != expected {
+		t.Fatalf("SS: expected %v, got %v", expected, got)
+	}
+	t.Log("SS Support:", got)
+}
+
+// TestTM tests TM() function (Thermal Monitor)
+func TestTM(t *testing.T) {
+	got := CPU.TM()
+	expected := CPU.LegacyFeatures&TM == TM
+	if got != expected {
+		t.Fatalf("TM: expected %v, got %v", expected, got)
+	}
+	t.Log("TM Support:", got)
+}
+
+// TestPBE tests PBE() function (Pending Break Enable)
+func TestPBE(t *testing.T) {
+	got := CPU.PBE()
+	expected := CPU.LegacyFeatures&PBE == PBE
+	if got != expected {
+		t.Fatalf("PBE: expected %v, got %v", expected, got)
+	}
+	t.Log("PBE Support:", got)
+}
+
+// TestDTES64 tests DTES64() function (64-bit DS Area)
+func TestDTES64(t *testing.T) {
+	got := CPU.DTES64()
+	expected := CPU.LegacyFeatures&DTES64 == DTES64
+	if got != expected {
+		t.Fatalf("DTES64: expected %v, got %v", expected, got)
+	}
+	t.Log("DTES64 Support:", got)
+}
+
+// TestMONITOR tests MONITOR() function (MONITOR/MWAIT instructions)
+func TestMONITOR(t *testing.T) {
+	got := CPU.MONITOR()
+	expected := CPU.LegacyFeatures&MONITOR == MONITOR
+	if got != expected {
+		t.Fatalf("MONITOR: expected %v, got %v", expected, got)
+	}
+	t.Log("MONITOR Support:", got)
+}
+
+// TestDSCPL tests DSCPL() function (CPL Qualified Debug Store)
+func TestDSCPL(t *testing.T) {
+	got := CPU.DSCPL()
+	expected := CPU.LegacyFeatures&DSCPL == DSCPL
+	if got != expected {
+		t.Fatalf("DSCPL: expected %v, got %v", expected, got)
+	}
+	t.Log("DSCPL Support:", got)
+}
+
+// TestSMX tests SMX() function (Safer Mode Extensions)
+func TestSMX(t *testing.T) {
+	got := CPU.SMX()
+	expected := CPU.LegacyFeatures&SMX == SMX
+	if got != expected {
+		t.Fatalf("SMX: expected %v, got %v", expected, got)
+	}
+	t.Log("SMX Support:", got)
+}
+
+// TestEST tests EST() function (Enhanced Intel SpeedStep technology)
+func TestEST(t *testing.T) {
+	got := CPU.EST()
+	expected := CPU.LegacyFeatures&EST == EST
+	if got != expected {
+		t.Fatalf("EST: expected %v, got %v", expected, got)
+	}
+	t.Log("EST Support:", got)
+}
+
+// TestTM2 tests TM2() function (Thermal Monitor 2)
+func TestTM2(t *testing.T) {
+	got := CPU.TM2()
+	expected := CPU.LegacyFeatures&TM2 == TM2
+	if got != expected {
+		t.Fatalf("TM2: expected %v, got %v", expected, got)
+	}
+	t.Log("TM2 Support:", got)
+}
+
+// TestCNXTID tests CNXTID() function (L1 Context ID)
+func TestCNXTID(t *testing.T) {
+	got := CPU.CNXTID()
+	expected := CPU.LegacyFeatures&CNXTID == CNXTID
+	if got != expected {
+		t.Fatalf("CNXTID: expected %v, got %v", expected, got)
+	}
+	t.Log("CNXTID Support:", got)
+}
+
+// TestSDBG tests SDBG() function (Silicon Debug)
+func TestSDBG(t *testing.T) {
+	got := CPU.SDBG()
+	expected := CPU.LegacyFeatures&SDBG == SDBG
+	if got != expected {
+		t.Fatalf("SDBG: expected %v, got %v", expected, got)
+	}
+	t.Log("SDBG Support:", got)
+}
+
+// TestXTPR tests XTPR() function (xTPR Update Control)
+func TestXTPR(t *testing.T) {
+	got := CPU.XTPR()
+	expected := CPU.LegacyFeatures&XTPR == XTPR
+	if got != expected {
+		t.Fatalf("XTPR: expected %v, got %v", expected, got)
+	}
+	t.Log("XTPR Support:", got)
+}
+
+// TestPDCM tests PDCM() function (Perfmon and Debug Capability)
+func TestPDCM(t *testing.T) {
+	got := CPU.PDCM()
+	expected := CPU.LegacyFeatures&PDCM == PDCM
+	if got != expected {
+		t.Fatalf("PDCM: expected %v, got %v", expected, got)
+	}
+	t.Log("PDCM Support:", got)
+}
+
+// TestDCA tests DCA() function (Direct Cache Access)
+func TestDCA(t *testing.T) {
+	got := CPU.DCA()
+	expected := CPU.LegacyFeatures&DCA == DCA
+	if got != expected {
+		t.Fatalf("DCA: expected %v, got %v", expected, got)
+	}
+	t.Log("DCA Support:", got)
+}
+
+// TestX2APIC tests X2APIC() function (x2APIC)
+func TestX2APIC(t *testing.T) {
+	got := CPU.X2APIC()
+	expected := CPU.LegacyFeatures&X2APIC == X2APIC
+	if got != expected {
+		t.Fatalf("X2APIC: expected %v, got %v", expected, got)
+	}
+	t.Log("X2APIC Support:", got)
+}
+
+// TestMOVBE tests MOVBE() function (MOVBE instruction)
+func TestMOVBE(t *testing.T) {
+	got := CPU.MOVBE()
+	expected := CPU.LegacyFeatures&MOVBE == MOVBE
+	if got != expected {
+		t.Fatalf("MOVBE: expected %v, got %v", expected, got)
+	}
+	t.Log("MOVBE Support:", got)
+}
+
+// TestTSCDEADLINE tests TSCDEADLINE() function (APIC timer TSC deadline mode)
+func TestTSCDEADLINE(t *testing.T) {
+	got := CPU.TSCDEADLINE()
+	expected := CPU.LegacyFeatures&TSCDEADLINE == TSCDEADLINE
+	if got != expected {
+		t.Fatalf("TSCDEADLINE: expected %v, got %v", expected, got)
+	}
+	t.Log("TSCDEADLINE Support:", got)
+}
+
+// TestXSAVE tests XSAVE() function (XSAVE/XRSTOR/XSETBV/XGETBV instructions)
+func TestXSAVE(t *testing.T) {
+	got := CPU.XSAVE()
+	expected := CPU.LegacyFeatures&XSAVE == XSAVE
+	if got != expected {
+		t.Fatalf("XSAVE: expected %v, got %v", expected, got)
+	}
+	t.Log("XSAVE Support:", got)
+}
+
+// TestOSXSAVE tests OSXSAVE() function (XSAVE enabled by the OS)
+func TestOSXSAVE(t *testing.T) {
+	got := CPU.OSXSAVE()
+	expected := CPU.LegacyFeatures&OSXSAVE == OSXSAVE
+	if got != expected {
+		t.Fatalf("OSXSAVE: expected %v, got %v", expected, got)
+	}
+	t.Log("OSXSAVE Support:", got)
+}
+
+// TestHYPERVISOR tests HYPERVISOR() function (Running under a hypervisor)
+func TestHYPERVISOR(t *testing.T) {
+	got := CPU.HYPERVISOR()
+	expected := CPU.LegacyFeatures&HYPERVISOR == HYPERVISOR
+	if got != expected {
+		t.Fatalf("HYPERVISOR: expected %v, got %v", expected, got)
+	}
+	t.Log("HYPERVISOR Support:", got)
+}
+
+// TestLAHF tests LAHF() function (LAHF/SAHF in 64-bit mode)
+func TestLAHF(t *testing.T) {
+	got := CPU.LAHF()
+	expected := CPU.AmdFeatures&LAHF == LAHF
+	if got != expected {
+		t.Fatalf("LAHF: expected %v, got %v", expected, got)
+	}
+	t.Log("LAHF Support:", got)
+}
+
+// TestCMPLEGACY tests CMPLEGACY() function (Core multi-processing legacy mode)
+func TestCMPLEGACY(t *testing.T) {
+	got := CPU.CMPLEGACY()
+	expected := CPU.AmdFeatures&CMPLEGACY == CMPLEGACY
+	if got != expected {
+		t.Fatalf("CMPLEGACY: expected %v, got %v", expected, got)
+	}
+	t.Log("CMPLEGACY Support:", got)
+}
+
+// TestSVM tests SVM() function (AMD Secure Virtual Machine)
+func TestSVM(t *testing.T) {
+	got := CPU.SVM()
+	expected := CPU.AmdFeatures&SVM == SVM
+	if got != expected {
+		t.Fatalf("SVM: expected %v, got %v", expected, got)
+	}
+	t.Log("SVM Support:", got)
+}
+
+// TestEXTAPICSPACE tests EXTAPICSPACE() function (Extended APIC register space)
+func TestEXTAPICSPACE(t *testing.T) {
+	got := CPU.EXTAPICSPACE()
+	expected := CPU.AmdFeatures&EXTAPICSPACE == EXTAPICSPACE
+	if got != expected {
+		t.Fatalf("EXTAPICSPACE: expected %v, got %v", expected, got)
+	}
+	t.Log("EXTAPICSPACE Support:", got)
+}
+
+// TestALTMOVCR8 tests ALTMOVCR8() function (LOCK MOV CR0 means MOV CR8)
+func TestALTMOVCR8(t *testing.T) {
+	got := CPU.ALTMOVCR8()
+	expected := CPU.AmdFeatures&ALTMOVCR8 == ALTMOVCR8
+	if got != expected {
+		t.Fatalf("ALTMOVCR8: expected %v, got %v", expected, got)
+	}
+	t.Log("ALTMOVCR8 Support:", got)
+}
+
+// TestABM tests ABM() function (Advanced Bit Manipulation)
+func TestABM(t *testing.T) {
+	got := CPU.ABM()
+	expected := CPU.AmdFeatures&ABM == ABM
+	if got != expected {
+		t.Fatalf("ABM: expected %v, got %v", expected, got)
+	}
+	t.Log("ABM Support:", got)
+}
+
+// TestMISALIGNSSE tests MISALIGNSSE() function (Misaligned SSE mode)
+func TestMISALIGNSSE(t *testing.T) {
+	got := CPU.MISALIGNSSE()
+	expected := CPU.AmdFeatures&MISALIGNSSE == MISALIGNSSE
+	if got != expected {
+		t.Fatalf("MISALIGNSSE: expected %v, got %v", expected, got)
+	}
+	t.Log("MISALIGNSSE Support:", got)
+}
+
+// TestPREFETCHW tests PREFETCHW() function (PREFETCH and PREFETCHW instructions)
+func TestPREFETCHW(t *testing.T) {
+	got := CPU.PREFETCHW()
+	expected := CPU.AmdFeatures&PREFETCHW == PREFETCHW
+	if got != expected {
+		t.Fatalf("PREFETCHW: expected %v, got %v", expected, got)
+	}
+	t.Log("PREFETCHW Support:", got)
+}
+
+// TestOSVW tests OSVW() function (OS Visible Workaround)
+func TestOSVW(t *testing.T) {
+	got := CPU.OSVW()
+	expected := CPU.AmdFeatures&OSVW == OSVW
+	if got != expected {
+		t.Fatalf("OSVW: expected %v, got %v", expected, got)
+	}
+	t.Log("OSVW Support:", got)
+}
+
+// TestIBS tests IBS() function (Instruction Based Sampling)
+func TestIBS(t *testing.T) {
+	got := CPU.IBS()
+	expected := CPU.AmdFeatures&IBS == IBS
+	if got != expected {
+		t.Fatalf("IBS: expected %v, got %v", expected, got)
+	}
+	t.Log("IBS Support:", got)
+}
+
+// TestSKINIT tests SKINIT() function (SKINIT and STGI instructions)
+func TestSKINIT(t *testing.T) {
+	got := CPU.SKINIT()
+	expected := CPU.AmdFeatures&SKINIT == SKINIT
+	if got != expected {
+		t.Fatalf("SKINIT: expected %v, got %v", expected, got)
+	}
+	t.Log("SKINIT Support:", got)
+}
+
+// TestWDT tests WDT() function (Watchdog timer)
+func TestWDT(t *testing.T) {
+	got := CPU.WDT()
+	expected := CPU.AmdFeatures&WDT == WDT
+	if got != expected {
+		t.Fatalf("WDT: expected %v, got %v", expected, got)
+	}
+	t.Log("WDT Support:", got)
+}
+
+// TestLWP tests LWP() function (Lightweight profiling)
+func TestLWP(t *testing.T) {
+	got := CPU.LWP()
+	expected := CPU.AmdFeatures&LWP == LWP
+	if got != expected {
+		t.Fatalf("LWP: expected %v, got %v", expected, got)
+	}
+	t.Log("LWP Support:", got)
+}
+
+// TestTCE tests TCE() function (Translation Cache Extension)
+func TestTCE(t *testing.T) {
+	got := CPU.TCE()
+	expected := CPU.AmdFeatures&TCE == TCE
+	if got != expected {
+		t.Fatalf("TCE: expected %v, got %v", expected, got)
+	}
+	t.Log("TCE Support:", got)
+}
+
+// TestNODEID tests NODEID() function (NodeId MSR)
+func TestNODEID(t *testing.T) {
+	got := CPU.NODEID()
+	expected := CPU.AmdFeatures&NODEID == NODEID
+	if got != expected {
+		t.Fatalf("NODEID: expected %v, got %v", expected, got)
+	}
+	t.Log("NODEID Support:", got)
+}
+
+// TestTOPOEXT tests TOPOEXT() function (Topology extensions)
+func TestTOPOEXT(t *testing.T) {
+	got := CPU.TOPOEXT()
+	expected := CPU.AmdFeatures&TOPOEXT == TOPOEXT
+	if got != expected {
+		t.Fatalf("TOPOEXT: expected %v, got %v", expected, got)
+	}
+	t.Log("TOPOEXT Support:", got)
+}
+
+// TestPERFCTREXTCORE tests PERFCTREXTCORE() function (Core performance counter extensions)
+func TestPERFCTREXTCORE(t *testing.T) {
+	got := CPU.PERFCTREXTCORE()
+	expected := CPU.AmdFeatures&PERFCTREXTCORE == PERFCTREXTCORE
+	if got != expected {
+		t.Fatalf("PERFCTREXTCORE: expected %v, got %v", expected, got)
+	}
+	t.Log("PERFCTREXTCORE Support:", got)
+}
+
+// TestPERFCTREXTNB tests PERFCTREXTNB() function (Northbridge performance counter extensions)
+func TestPERFCTREXTNB(t *testing.T) {
+	got := CPU.PERFCTREXTNB()
+	expected := CPU.AmdFeatures&PERFCTREXTNB == PERFCTREXTNB
+	if got != expected {
+		t.Fatalf("PERFCTREXTNB: expected %v, got %v", expected, got)
+	}
+	t.Log("PERFCTREXTNB Support:", got)
+}
+
+// TestDATABKPTEXT tests DATABKPTEXT() function (Data breakpoint extension)
+func TestDATABKPTEXT(t *testing.T) {
+	got := CPU.DATABKPTEXT()
+	expected := CPU.AmdFeatures&DATABKPTEXT == DATABKPTEXT
+	if got != expected {
+		t.Fatalf("DATABKPTEXT: expected %v, got %v", expected, got)
+	}
+	t.Log("DATABKPTEXT Support:", got)
+}
+
+// TestPERFTSC tests PERFTSC() function (Performance time-stamp counter)
+func TestPERFTSC(t *testing.T) {
+	got := CPU.PERFTSC()
+	expected := CPU.AmdFeatures&PERFTSC == PERFTSC
+	if got != expected {
+		t.Fatalf("PERFTSC: expected %v, got %v", expected, got)
+	}
+	t.Log("PERFTSC Support:", got)
+}
+
+// TestMONITORX tests MONITORX() function (MONITORX and MWAITX instructions)
+func TestMONITORX(t *testing.T) {
+	got := CPU.MONITORX()
+	expected := CPU.AmdFeatures&MONITORX == MONITORX
+	if got != expected {
+		t.Fatalf("MONITORX: expected %v, got %v", expected, got)
+	}
+	t.Log("MONITORX Support:", got)
+}
+
+// TestCLZERO tests CLZERO() function (CLZERO instruction)
+func TestCLZERO(t *testing.T) {
+	got := CPU.CLZERO()
+	expected := CPU.AmdFeatures&CLZERO == CLZERO
+	if got != expected {
+		t.Fatalf("CLZERO: expected %v, got %v", expected, got)
+	}
+	t.Log("CLZERO Support:", got)
+}
+
+// TestINSTRETCNT tests INSTRETCNT() function (Instruction Retired Counter MSR)
+func TestINSTRETCNT(t *testing.T) {
+	got := CPU.INSTRETCNT()
+	expected := CPU.AmdFeatures&INSTRETCNT == INSTRETCNT
+	if got != expected {
+		t.Fatalf("INSTRETCNT: expected %v, got %v", expected, got)
+	}
+	t.Log("INSTRETCNT Support:", got)
+}
+
+// TestRSTRFPERR tests RSTRFPERR() function (FXSAVE/XSAVE always save/restore the error pointers)
+func TestRSTRFPERR(t *testing.T) {
+	got := CPU.RSTRFPERR()
+	expected := CPU.AmdFeatures&RSTRFPERR == RSTRFPERR
+	if got != expected {
+		t.Fatalf("RSTRFPERR: expected %v, got %v", expected, got)
+	}
+	t.Log("RSTRFPERR Support:", got)
+}
+
+// TestINVLPGB tests INVLPGB() function (INVLPGB and TLBSYNC instructions)
+func TestINVLPGB(t *testing.T) {
+	got := CPU.INVLPGB()
+	expected := CPU.AmdFeatures&INVLPGB == INVLPGB
+	if got != expected {
+		t.Fatalf("INVLPGB: expected %v, got %v", expected, got)
+	}
+	t.Log("INVLPGB Support:", got)
+}
+
+// TestRDPRU tests RDPRU() function (RDPRU instruction)
+func TestRDPRU(t *testing.T) {
+	got := CPU.RDPRU()
+	expected := CPU.AmdFeatures&RDPRU == RDPRU
+	if got != expected {
+		t.Fatalf("RDPRU: expected %v, got %v", expected, got)
+	}
+	t.Log("RDPRU Support:", got)
+}
+
+// TestMCOMMIT tests MCOMMIT() function (MCOMMIT instruction)
+func TestMCOMMIT(t *testing.T) {
+	got := CPU.MCOMMIT()
+	expected := CPU.AmdFeatures&MCOMMIT == MCOMMIT
+	if got != expected {
+		t.Fatalf("MCOMMIT: expected %v, got %v", expected, got)
+	}
+	t.Log("MCOMMIT Support:", got)
+}
+
+// TestWBNOINVD tests WBNOINVD() function (WBNOINVD instruction)
+func TestWBNOINVD(t *testing.T) {
+	got := CPU.WBNOINVD()
+	expected := CPU.AmdFeatures&WBNOINVD == WBNOINVD
+	if got != expected {
+		t.Fatalf("WBNOINVD: expected %v, got %v", expected, got)
+	}
+	t.Log("WBNOINVD Support:", got)
+}
+
 // TestMPX tests MPX() function (Intel MPX (Memory Protection Extensions))
 func TestMPX(t *testing.T) {
 	got := CPU.MPX()
@@ -745,6 +2063,54 @@ func TestAmxStrings(t *testing.T) {
 	t.Log("AmxFlags Strings:", got)
 }
 
+// TestExtStrings tests ExtFlags.Strings()
+func TestExtStrings(t *testing.T) {
+	ef := ExtFlags(0)
+	ef |= (AVXVNNI | SHA512X86 | RAOINT)
+	got := ef.Strings()
+	expected := []string{"AVXVNNI", "SHA512", "RAOINT"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("ExtFlags Strings: expected %v, got %v", expected, got)
+	}
+	t.Log("ExtFlags Strings:", got)
+}
+
+// TestSysStrings tests SysFlags.Strings()
+func TestSysStrings(t *testing.T) {
+	sf := SysFlags(0)
+	sf |= (PCID | PKU | TME)
+	got := sf.Strings()
+	expected := []string{"PCID", "PKU", "TME"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("SysFlags Strings: expected %v, got %v", expected, got)
+	}
+	t.Log("SysFlags Strings:", got)
+}
+
+// TestLegacyStrings tests LegacyFlags.Strings()
+func TestLegacyStrings(t *testing.T) {
+	lf := LegacyFlags(0)
+	lf |= (FPU | MOVBE | HYPERVISOR)
+	got := lf.Strings()
+	expected := []string{"FPU", "MOVBE", "HYPERVISOR"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("LegacyFlags Strings: expected %v, got %v", expected, got)
+	}
+	t.Log("LegacyFlags Strings:", got)
+}
+
+// TestAmdStrings tests AmdFlags.Strings()
+func TestAmdStrings(t *testing.T) {
+	af := AmdFlags(0)
+	af |= (LAHF | SVM | WBNOINVD)
+	got := af.Strings()
+	expected := []string{"LAHF", "SVM", "WBNOINVD"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("AmdFlags Strings: expected %v, got %v", expected, got)
+	}
+	t.Log("AmdFlags Strings:", got)
+}
+
 // TestVendor writes the detected vendor. Will be 0 if unknown
 func TestVendor(t *testing.T) {
 	t.Log("Vendor ID:", CPU.VendorID)
@@ -810,11 +2176,83 @@ func TestVIA(t *testing.T) {
 	t.Log("TestVIA:", got)
 }
 
+// Cyrix returns true if vendor is recognized as Cyrix
+func TestCyrix(t *testing.T) {
+	got := CPU.Cyrix()
+	expected := CPU.VendorID == Cyrix
+	if got != expected {
+		t.Fatalf("TestCyrix: expected %v, got %v", expected, got)
+	}
+	t.Log("TestCyrix:", got)
+}
+
+// TestVendorString tests Vendor.String() and ParseVendor
+func TestVendorString(t *testing.T) {
+	for _, test := range []struct {
+		v    Vendor
+		name string
+	}{
+		{Other, "Other"},
+		{Intel, "Intel"},
+		{Zhaoxin, "Zhaoxin"},
+		{QEMU, "QEMU"},
+		{MicrosoftXTA, "MicrosoftXTA"},
+		{Vendor(1000), "Vendor(1000)"},
+	} {
+		if got := test.v.String(); got != test.name {
+			t.Errorf("expected %q, got %q", test.name, got)
+		}
+	}
+	for _, v := range []Vendor{Other, Intel, AMD, Hygon, Cyrix, Parallels, AppleRosetta, ARM, NVIDIA} {
+		if got := ParseVendor(v.String()); got != v {
+			t.Errorf("ParseVendor(%q): expected %v, got %v", v.String(), v, got)
+		}
+	}
+	if got := ParseVendor("amd"); got != AMD {
+		t.Errorf("expected AMD, got %v", got)
+	}
+	if got := ParseVendor(" lrpepyh  vr"); got != Parallels {
+		t.Errorf("expected Parallels, got %v", got)
+	}
+	if got := ParseVendor("unknown"); got != Other {
+		t.Errorf("expected Other, got %v", got)
+	}
+	t.Log("Vendor:", CPU.VendorID)
+}
+
 // Test VM function
 func TestVM(t *testing.T) {
 	t.Log("Vendor ID:", CPU.VM())
 }
 
+// Test TDX guest detection and the confidential computing report
+func TestTDXGuest(t *testing.T) {
+	const tdx = `
+CPUID 00000000: 00000021-756E6547-6C65746E-49656E69
+CPUID 00000001: 000806F8-00000800-80000000-00000000
+CPUID 00000021: 00000000-65746E49-20202020-5844546C
+`
+	restore := mockCPU([]byte(tdx))
+	Detect()
+	got, vm, cc := CPU.TDXGuest(), CPU.VM(), CPU.ConfidentialComputing()
+	restore()
+	Detect()
+	if !got {
+		t.Fatal("TDX guest not detected")
+	}
+	// VM is based on the vendor only.
+	if vm {
+		t.Fatal("unexpected VM")
+	}
+	if cc.Technology != CCTDX || !cc.Active {
+		t.Fatalf("unexpected confidential computing report: %+v", cc)
+	}
+	if cc.Technology.String() != "TDX" {
+		t.Fatalf("unexpected technology name: %v", cc.Technology)
+	}
+	t.Log("Confidential computing:", CPU.ConfidentialComputing())
+}
+
 // TSX returns true if cpu supports transactional sync extensions.
 func TestCPUInfo_TSX(t *testing.T) {
 	got := CPU.TSX()
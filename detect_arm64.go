@@ -7,6 +7,7 @@ package cpuid
 func getMidr() (midr uint64)
 func getProcFeatures() (procFeatures uint64)
 func getInstAttributes() (instAttrReg0, instAttrReg1 uint64)
+func getVectorLength() (vl uint64)
 
 func initCPU() {
 	cpuid = func(uint32) (a, b, c, d uint32) { return 0, 0, 0, 0 }
@@ -16,33 +17,37 @@ func initCPU() {
 }
 
 func addInfo(c *CPUInfo) {
-	// ARM64 disabled for now.
-	if true {
-		return
-	}
-	// 	midr := getMidr()
-
-	// MIDR_EL1 - Main ID Register
-	//  x--------------------------------------------------x
-	//  | Name                         |  bits   | visible |
-	//  |--------------------------------------------------|
-	//  | Implementer                  | [31-24] |    y    |
-	//  |--------------------------------------------------|
-	//  | Variant                      | [23-20] |    y    |
-	//  |--------------------------------------------------|
-	//  | Architecture                 | [19-16] |    y    |
-	//  |--------------------------------------------------|
-	//  | PartNum                      | [15-4]  |    y    |
-	//  |--------------------------------------------------|
-	//  | Revision                     | [3-0]   |    y    |
-	//  x--------------------------------------------------x
+	hwcap, hwcap2 := parseAuxv(osAuxv())
+	c.Arm, c.SmeFeatures = hwcapFlags(hwcap, hwcap2)
+	if hwcap&hwcapCPUID != 0 {
+		// The kernel traps and emulates MRS on the ID registers,
+		// so reading them from EL0 will not fault.
+		c.Arm |= idRegisterFlags()
 
-	// 	fmt.Printf(" implementer:  0x%02x\n", (midr>>24)&0xff)
-	// 	fmt.Printf("     variant:   0x%01x\n", (midr>>20)&0xf)
-	// 	fmt.Printf("architecture:   0x%01x\n", (midr>>16)&0xf)
-	// 	fmt.Printf("    part num: 0x%03x\n", (midr>>4)&0xfff)
-	// 	fmt.Printf("    revision:   0x%01x\n", (midr>>0)&0xf)
+		// MIDR_EL1 - Main ID Register
+		//  x--------------------------------------------------x
+		//  | Name                         |  bits   | visible |
+		//  |--------------------------------------------------|
+		//  | Implementer                  | [31-24] |    y    |
+		//  |--------------------------------------------------|
+		//  | Variant                      | [23-20] |    y    |
+		//  |--------------------------------------------------|
+		//  | Architecture                 | [19-16] |    y    |
+		//  |--------------------------------------------------|
+		//  | PartNum                      | [15-4]  |    y    |
+		//  |--------------------------------------------------|
+		//  | Revision                     | [3-0]   |    y    |
+		//  x--------------------------------------------------x
+		c.armIdentify(getMidr())
+	} else {
+		c.armIdentify(sysfsMidr())
+	}
+	c.ArmVectorLength = armVectorLength(c.Arm&SVE != 0, c.SmeFeatures&SME != 0, getVectorLength)
+}
 
+// idRegisterFlags returns the features described by the ID registers.
+// Must only be called if HWCAP_CPUID is set.
+func idRegisterFlags() ArmFlags {
 	procFeatures := getProcFeatures()
 
 	// ID_AA64PFR0_EL1 - Processor Feature Register 0
@@ -69,9 +74,9 @@ func addInfo(c *CPUInfo) {
 	// x--------------------------------------------------x
 
 	var f ArmFlags
-	// if procFeatures&(0xf<<48) != 0 {
-	// 	fmt.Println("DIT")
-	// }
+	if procFeatures&(0xf<<48) != 0 {
+		f |= DIT
+	}
 	if procFeatures&(0xf<<32) != 0 {
 		f |= SVE
 	}
@@ -84,7 +89,7 @@ func addInfo(c *CPUInfo) {
 			f |= ASIMDHP
 		}
 	}
-	if procFeatures&(0xf<<16) != 0 {
+	if procFeatures&(0xf<<16) != 15<<16 {
 		f |= FP
 	}
 
@@ -121,12 +126,16 @@ func addInfo(c *CPUInfo) {
 	// | AES                          | [7-4]   |    y    |
 	// x--------------------------------------------------x
 
-	// if instAttrReg0&(0xf<<52) != 0 {
-	// 	fmt.Println("TS")
-	// }
-	// if instAttrReg0&(0xf<<48) != 0 {
-	// 	fmt.Println("FHM")
-	// }
+	if instAttrReg0&(0xf<<52) != 0 {
+		f |= FLAGM
+	}
+	if instAttrReg0&(0xf<<52) == 2<<52 {
+		// 0b0010 --> As 0b0001, plus AXFLAG and XAFLAG instructions implemented.
+		f |= FLAGM2
+	}
+	if instAttrReg0&(0xf<<48) != 0 {
+		f |= ASIMDFHM
+	}
 	if instAttrReg0&(0xf<<44) != 0 {
 		f |= ASIMDDP
 	}
@@ -191,12 +200,12 @@ func addInfo(c *CPUInfo) {
 	// | DPB                          | [3-0]   |    y    |
 	// x--------------------------------------------------x
 
-	// if instAttrReg1&(0xf<<28) != 0 {
-	// 	fmt.Println("GPI")
-	// }
-	if instAttrReg1&(0xf<<28) != 24 {
+	if instAttrReg1&(0xf<<24) != 0 {
 		f |= GPA
 	}
+	if instAttrReg1&(0xf<<28) != 0 || instAttrReg1&(0xf<<24) != 0 {
+		f |= PACG
+	}
 	if instAttrReg1&(0xf<<20) != 0 {
 		f |= LRCPC
 	}
@@ -206,14 +215,15 @@ func addInfo(c *CPUInfo) {
 	if instAttrReg1&(0xf<<12) != 0 {
 		f |= JSCVT
 	}
-	// if instAttrReg1&(0xf<<8) != 0 {
-	// 	fmt.Println("API")
-	// }
-	// if instAttrReg1&(0xf<<4) != 0 {
-	// 	fmt.Println("APA")
-	// }
+	if instAttrReg1&(0xf<<8) != 0 || instAttrReg1&(0xf<<4) != 0 {
+		f |= PACA
+	}
 	if instAttrReg1&(0xf<<0) != 0 {
 		f |= DCPOP
 	}
-	c.Arm = f
+	if instAttrReg1&(0xf<<0) == 2<<0 {
+		// 0b0010 --> As 0b0001, plus DC CVADP instruction implemented.
+		f |= DCPODP
+	}
+	return f
 }
@@ -22,12 +22,23 @@ func addInfo(c *CPUInfo) {
 	c.BrandName = brandName()
 	c.CacheLine = cacheLine()
 	c.Family, c.Model = familyModel()
-	c.Features, c.AmxFeatures = support()
+	c.Features, c.AmxFeatures, c.ExtFeatures = support()
+	c.SysFeatures = sysSupport()
+	c.LegacyFeatures = legacySupport()
+	c.AmdFeatures = amdSupport()
 	c.SGX = hasSGX(c.Features&SGX != 0, c.Features&SGXLC != 0)
+	c.KeyLocker = keyLocker(c.ExtFeatures&KEYLOCKER != 0)
+	c.PMU = pmu()
+	c.SEV = sev()
+	c.SVMFeatures = svm()
+	c.tdxGuest = tdxGuest()
+	c.avx10Version, c.avx10Lengths = avx10()
+	c.RDT = resourceDirector()
 	c.ThreadsPerCore = threadsPerCore()
 	c.LogicalCores = logicalCores()
 	c.PhysicalCores = physicalCores()
 	c.VendorID, c.VendorString = vendorID()
 	c.Hz = hertz(c.BrandName)
+	c.vendorExtras()
 	c.cacheSize()
 }
@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -33,8 +34,18 @@ func mockCPU(def []byte) func() {
 	lines := strings.Split(string(def), "\n")
 	anyfound := false
 	fakeID := make(fakecpuid)
+	var xcr0, xcr0found = uint32(0), false
 	for _, line := range lines {
 		line = strings.Trim(line, "\r\t ")
+		// Mocked XCR0 value, "XGETBV 00000000: eax-edx".
+		// Dumps do not contain this, so unless specified no extended state is enabled.
+		if strings.HasPrefix(line, "XGETBV 00000000:") && !xcr0found {
+			var edx uint32
+			if n, err := fmt.Sscanf(line, "XGETBV 00000000: %x-%x", &xcr0, &edx); err == nil && n == 2 {
+				xcr0found = true
+			}
+			continue
+		}
 		if !strings.HasPrefix(line, "CPUID") {
 			continue
 		}
@@ -96,7 +107,7 @@ func mockCPU(def []byte) func() {
 	}(idfuncs{cpuid: cpuid, cpuidex: cpuidex, xgetbv: xgetbv})
 
 	cpuid = func(op uint32) (eax, ebx, ecx, edx uint32) {
-		if op == 0x80000000 || op == 0 {
+		if op == 0x80000000 || op == 0 || op == 0xc0000000 {
 			var ok bool
 			_, ok = fakeID[op]
 			if !ok {
@@ -105,7 +116,7 @@ func mockCPU(def []byte) func() {
 		}
 		first, ok := fakeID[op]
 		if !ok {
-			if op > maxFunctionID() {
+			if op > maxFunctionID() && (op < 0x80000000 || op > maxExtendedFunction()) {
 				panic(fmt.Sprintf("Base not found: %v, request:%#v\n", fakeID, op))
 			} else {
 				// we have some entries missing
@@ -125,7 +136,7 @@ func mockCPU(def []byte) func() {
 		}
 		first, ok := fakeID[op]
 		if !ok {
-			if op > maxExtendedFunction() {
+			if (op < 0x80000000 && op > maxFunctionID()) || (op >= 0x80000000 && op > maxExtendedFunction()) {
 				panic(fmt.Sprintf("Extended not found Info: %v, request:%#v, %#v\n", fakeID, op, op2))
 			} else {
 				// we have some entries missing
@@ -146,10 +157,12 @@ func mockCPU(def []byte) func() {
 		}
 		second := first[0]
 		// ECX bit 26 must be set
-		if (second[2] & 1 << 26) == 0 {
+		if (second[2] & (1 << 26)) == 0 {
 			panic(fmt.Sprintf("XGETBV not supported %v", fakeID))
 		}
-		// We don't have any data to return, unfortunately
+		if index == 0 {
+			return xcr0, 0
+		}
 		return 0, 0
 	}
 	return restorer
@@ -210,3 +223,679 @@ func TestMocks(t *testing.T) {
 	Detect()
 
 }
+
+// mockFile will mock the CPU from the dump in testdata/cpuid_data.zip
+// whose name ends with the given suffix.
+// The returned function will restore the previous CPU.
+func mockFile(t *testing.T, suffix string) func() {
+	zr, err := zip.OpenReader("testdata/cpuid_data.zip")
+	if err != nil {
+		t.Skip("No testdata:", err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, suffix) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc.Close()
+		restore := mockCPU(content)
+		Detect()
+		return func() {
+			restore()
+			Detect()
+		}
+	}
+	t.Fatal("testdata not found:", suffix)
+	return nil
+}
+
+func TestMockPMU(t *testing.T) {
+	restore := mockFile(t, "GenuineIntel00906EA_Coffeelake_CPUID.txt")
+	p := CPU.PMU
+	restore()
+	if p.Version != 4 || p.GPCounters != 4 || p.GPCounterWidth != 48 {
+		t.Fatalf("unexpected general purpose counters: %+v", p)
+	}
+	if p.FixedCounters != 3 || p.FixedCounterWidth != 48 {
+		t.Fatalf("unexpected fixed counters: %+v", p)
+	}
+	if p.EventsLength != 7 || p.UnavailableEvents != 0 {
+		t.Fatalf("unexpected events: %+v", p)
+	}
+	if p.LBR.Available || p.IBS.Available || p.PerfMonV2 {
+		t.Fatalf("unexpected features: %+v", p)
+	}
+
+	restore = mockFile(t, "AuthenticAMD0800F11_K17_Zen3_CPUID.txt")
+	p = CPU.PMU
+	restore()
+	if p.Version != 0 || p.GPCounters != 6 || p.GPCounterWidth != 48 || p.NBCounters != 4 {
+		t.Fatalf("unexpected AMD counters: %+v", p)
+	}
+}
+
+func TestMockSEV(t *testing.T) {
+	restore := mockFile(t, "AuthenticAMD0800F11_K17_Zen3_CPUID.txt")
+	s := CPU.SEV
+	restore()
+	if !s.SME || !s.SEV || s.SEVES || s.SEVSNP {
+		t.Fatalf("unexpected SEV features: %+v", s)
+	}
+	if s.CBitPosition != 47 || s.PhysAddrReduction != 5 || s.NumEncryptedGuests != 15 {
+		t.Fatalf("unexpected SEV parameters: %+v", s)
+	}
+	if s.Active() {
+		t.Fatal("SEV reported active on host")
+	}
+}
+
+func TestMockSGX(t *testing.T) {
+	restore := mockFile(t, "GenuineIntel00706E5_IceLakeY_CPUID.txt")
+	s := CPU.SGX
+	restore()
+	if !s.Available || !s.SGX1Supported || !s.SGX2Supported || !s.EDMMSupported {
+		t.Fatalf("unexpected SGX support: %+v", s)
+	}
+	if !s.ENCLVSupported || !s.ENCLSCSupported || !s.KSSSupported || s.AEXNotifySupported {
+		t.Fatalf("unexpected SGX extensions: %+v", s)
+	}
+	if s.MiscSelect != 1 || s.Attributes != 0xb6 || s.XFRM != 0x2e7 {
+		t.Fatalf("unexpected SGX attributes: %+v", s)
+	}
+	if len(s.EPCSections) != 1 || s.EPCSize != 0xbc00000 || s.EPCSections[0].BaseAddress != 0x30180000 {
+		t.Fatalf("unexpected EPC sections: %+v", s)
+	}
+}
+
+func TestMockResourceDirector(t *testing.T) {
+	restore := mockFile(t, "AuthenticAMD0830F10_K17_Rome_CPUID.txt")
+	r := CPU.RDT
+	restore()
+	m := r.Monitoring
+	if m.Leaf != 0xf || m.MaxRMID != 255 || m.L3MaxRMID != 255 || m.ScaleFactor != 64 || m.CounterWidth != 24 {
+		t.Fatalf("unexpected monitoring: %+v", m)
+	}
+	if !m.Occupancy || !m.TotalBW || !m.LocalBW {
+		t.Fatalf("unexpected monitoring events: %+v", m)
+	}
+	if r.L3CAT != (RDTAllocation{Leaf: 0x10, MaskLength: 16, NumCOS: 16, CDP: true}) {
+		t.Fatalf("unexpected L3 CAT: %+v", r.L3CAT)
+	}
+	if r.L2CAT.Leaf != 0 {
+		t.Fatalf("unexpected L2 CAT: %+v", r.L2CAT)
+	}
+	if r.MBA.Leaf != 0x80000020 {
+		t.Fatalf("unexpected MBA: %+v", r.MBA)
+	}
+
+	restore = mockFile(t, "GenuineIntel0050654_SkylakeXeon_CPUID.txt")
+	r = CPU.RDT
+	restore()
+	if r.Monitoring.MaxRMID != 0x8f || r.L3CAT.Leaf != 0x10 || r.MBA.Leaf != 0x10 || r.L2CAT.Leaf != 0 {
+		t.Fatalf("unexpected resource director: %+v", r)
+	}
+}
+
+func TestMockLeaf7Sub1(t *testing.T) {
+	// Leaf 7 subleaf 1 with all known bits set, but no OS support for AVX.
+	const cpu = `
+CPUID 00000000: 00000007-756E6547-6C65746E-49656E69
+CPUID 00000001: 000B06A2-00800800-7FFAFBFF-BFEBFBFF
+CPUID 00000007: 00000001-00000000-00000000-00800000
+CPUID 00000007: 0CC81E9F-00000000-00000000-00004430
+`
+	restore := mockCPU([]byte(cpu))
+	Detect()
+	got := CPU.ExtFeatures
+	restore()
+	Detect()
+	expected := RAOINT | CMPCCXADD | FZLRM | FSRS | FSRCS | WRMSRNS | HRESET | LAM | MSRLIST | PREFETCHI
+	if got != expected {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMockLeaf7Sub1XCR0(t *testing.T) {
+	// AVX, AVX2, AVX512F and AVX512FP16, with the VEX encoded leaf 7 subleaf 1 features.
+	const cpu = `
+CPUID 00000000: 00000007-756E6547-6C65746E-49656E69
+CPUID 00000001: 000B06A2-00800800-7FFAFBFF-BFEBFBFF
+CPUID 00000007: 00000001-00010020-00000000-00800000
+CPUID 00000007: 00800010-00000000-00000000-00000430
+`
+	const vex = AVXVNNI | AVXIFMA | AVXVNNIINT8 | AVXVNNIINT16 | AVXNECONVERT
+	for _, test := range []struct {
+		xcr0     uint32
+		expected ExtFlags
+	}{
+		{xcr0: 0x3},
+		{xcr0: 0x7, expected: vex},
+		{xcr0: 0xe7, expected: vex | AVX512FP16},
+	} {
+		restore := mockCPU([]byte(fmt.Sprintf("%sXGETBV 00000000: %08x-00000000\n", cpu, test.xcr0)))
+		Detect()
+		got := CPU.ExtFeatures & (vex | AVX512FP16)
+		restore()
+		Detect()
+		if got != test.expected {
+			t.Fatalf("xcr0 %x: expected %v, got %v", test.xcr0, test.expected, got)
+		}
+	}
+}
+
+func TestMockAVX10(t *testing.T) {
+	const cpu = `
+CPUID 00000000: 00000024-756E6547-6C65746E-49656E69
+CPUID 00000001: 000C06F2-00800800-7FFAFBFF-BFEBFBFF
+CPUID 00000007: 00000001-00000000-00000000-00000000
+CPUID 00000007: 00000000-00000000-00000000-00080000
+CPUID 00000024: 00000000-00070001-00000000-00000000
+`
+	for _, test := range []struct {
+		xcr0    uint32
+		version int
+		lengths []int
+	}{
+		{xcr0: 0x7, version: 0},
+		{xcr0: 0x27, version: 1, lengths: []int{128, 256}},
+		{xcr0: 0xe7, version: 1, lengths: []int{128, 256, 512}},
+	} {
+		xcr0 := test.xcr0
+		restore := mockCPU([]byte(fmt.Sprintf("%sXGETBV 00000000: %08x-00000000\n", cpu, xcr0)))
+		Detect()
+		c := CPU
+		restore()
+		Detect()
+		if c.AVX10Version() != test.version || !reflect.DeepEqual(c.AVX10VectorLengths(), test.lengths) {
+			t.Fatalf("xcr0 %x: unexpected AVX10 version %d, lengths %v", xcr0, c.AVX10Version(), c.AVX10VectorLengths())
+		}
+		if c.AVX10() != (test.version > 0) {
+			t.Fatalf("xcr0 %x: unexpected AVX10 flag", xcr0)
+		}
+		// AVX10/512 implies AVX-512.
+		has512 := len(test.lengths) == 3
+		if c.AVX512F() != has512 || c.AVX512BW() != has512 || c.AVX512FP16() != has512 {
+			t.Fatalf("xcr0 %x: AVX-512 flags inconsistent with AVX10 vector lengths: %v", xcr0, c.Features)
+		}
+	}
+}
+
+func TestMockAPX(t *testing.T) {
+	const cpu = `
+CPUID 00000000: 00000007-756E6547-6C65746E-49656E69
+CPUID 00000001: 000C06F2-00800800-7FFAFBFF-BFEBFBFF
+CPUID 00000007: 00000001-00000000-00000000-00000000
+CPUID 00000007: 00000000-00000000-00000000-00200000
+`
+	for _, test := range []struct {
+		xcr0     uint32
+		expected bool
+	}{
+		{xcr0: 0, expected: false},
+		{xcr0: 0xe7, expected: false},
+		{xcr0: 0x800e7, expected: true},
+	} {
+		restore := mockCPU([]byte(fmt.Sprintf("%sXGETBV 00000000: %08x-00000000\n", cpu, test.xcr0)))
+		Detect()
+		got, avx := CPU.APX(), CPU.AVX()
+		restore()
+		Detect()
+		if got != test.expected {
+			t.Fatalf("xcr0 %x: expected APX %v, got %v", test.xcr0, test.expected, got)
+		}
+		if avx != (test.xcr0&6 == 6) {
+			t.Fatalf("xcr0 %x: unexpected AVX %v", test.xcr0, avx)
+		}
+	}
+}
+
+func TestMockLegacy(t *testing.T) {
+	restore := mockFile(t, "GenuineIntel00906EA_Coffeelake_CPUID.txt")
+	got := CPU.LegacyFeatures
+	restore()
+	expected := FPU | TSC | CX8 | APIC | SEP | CLFSH | FXSR | SS | TM | PBE |
+		MONITOR | EST | TM2 | X2APIC | MOVBE | TSCDEADLINE | XSAVE | OSXSAVE
+	if got&expected != expected {
+		t.Fatalf("missing features, expected %v, got %v", expected, got)
+	}
+	if got&(CNXTID|DCA|HYPERVISOR) != 0 {
+		t.Fatalf("unexpected features: %v", got)
+	}
+}
+
+func TestMockAmdFeatures(t *testing.T) {
+	restore := mockFile(t, "AuthenticAMD0800F11_K17_Zen3_CPUID.txt")
+	got := CPU.AmdFeatures
+	popcnt := CPU.Popcnt()
+	restore()
+	expected := LAHF | CMPLEGACY | SVM | EXTAPICSPACE | ALTMOVCR8 | ABM | MISALIGNSSE |
+		PREFETCHW | OSVW | SKINIT | WDT | TCE | TOPOEXT | PERFCTREXTCORE | PERFCTREXTNB |
+		DATABKPTEXT | MONITORX | CLZERO | INSTRETCNT | RSTRFPERR
+	if got != expected {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if !popcnt {
+		t.Fatal("expected POPCNT")
+	}
+
+	restore = mockFile(t, "GenuineIntel00906EA_Coffeelake_CPUID.txt")
+	got = CPU.AmdFeatures
+	lzcnt := CPU.Lzcnt()
+	restore()
+	if got != LAHF|ABM|PREFETCHW {
+		t.Fatalf("expected LAHF, ABM and PREFETCHW, got %v", got)
+	}
+	if !lzcnt {
+		t.Fatal("expected LZCNT")
+	}
+}
+
+func TestMockSVM(t *testing.T) {
+	restore := mockFile(t, "AuthenticAMD0800F11_K17_Zen3_CPUID.txt")
+	got := CPU.SVMFeatures
+	restore()
+	expected := SVMSupport{
+		Revision:             1,
+		NumASID:              32768,
+		NPT:                  true,
+		LBRVirt:              true,
+		SVML:                 true,
+		NRIPSave:             true,
+		TSCRateMSR:           true,
+		VMCBClean:            true,
+		FlushByASID:          true,
+		DecodeAssists:        true,
+		PauseFilter:          true,
+		PauseFilterThreshold: true,
+		AVIC:                 true,
+		VMSAVEVirt:           true,
+		VGIF:                 true,
+	}
+	if got != expected {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+
+	restore = mockFile(t, "AuthenticAMD0830F10_K17_Rome_CPUID.txt")
+	got = CPU.SVMFeatures
+	restore()
+	if !got.GMET || got.X2AVIC || got.SSS {
+		t.Fatalf("unexpected GMET/X2AVIC/SSS: %+v", got)
+	}
+
+	restore = mockFile(t, "GenuineIntel00906EA_Coffeelake_CPUID.txt")
+	got = CPU.SVMFeatures
+	restore()
+	if got != (SVMSupport{}) {
+		t.Fatalf("expected no SVM on Intel, got %+v", got)
+	}
+}
+
+func TestMockDataMovement(t *testing.T) {
+	restore := mockFile(t, "GenuineIntel0050654_SkylakeXeon_CPUID.txt")
+	got := CPU.ExtFeatures
+	restore()
+	if got&(CLFLUSHOPT|CLWB) != CLFLUSHOPT|CLWB || got&(FSRM|PTWRITE) != 0 {
+		t.Fatalf("unexpected features: %v", got)
+	}
+
+	restore = mockFile(t, "GenuineIntel00706E5_IceLakeY_CPUID.txt")
+	got = CPU.ExtFeatures
+	restore()
+	if got&(CLFLUSHOPT|FSRM) != CLFLUSHOPT|FSRM || got&CLWB != 0 {
+		t.Fatalf("unexpected features: %v", got)
+	}
+
+	const cpu = `
+CPUID 00000000: 00000014-756E6547-6C65746E-49656E69
+CPUID 00000007: 00000000-%08X-3A000021-00054030 [SL 00]
+CPUID 00000014: 00000001-00000010-00000000-00000000 [SL 00]
+`
+	expected := PREFETCHWT1 | WAITPKG | CLDEMOTE | MOVDIRI | MOVDIR64B | ENQCMD |
+		FSRM | UINTR | SERIALIZE | TSXLDTRK | PCONFIG
+	// Leaf 0x14 is only valid with Intel PT, CPUID.7.0:EBX[25].
+	for _, pt := range []bool{false, true} {
+		var ebx uint32
+		want := expected
+		if pt {
+			ebx = 1 << 25
+			want |= PTWRITE
+		}
+		restore = mockCPU([]byte(fmt.Sprintf(cpu, ebx)))
+		Detect()
+		got = CPU.ExtFeatures
+		restore()
+		Detect()
+		if got != want {
+			t.Fatalf("PT %v: expected %v, got %v", pt, want, got)
+		}
+	}
+}
+
+func TestMockKeyLocker(t *testing.T) {
+	for _, test := range []struct {
+		leaf19   string
+		flags    ExtFlags
+		expected KeyLocker
+	}{
+		{
+			leaf19:   "00000000-00000000-00000000-00000000",
+			flags:    KEYLOCKER,
+			expected: KeyLocker{Available: true},
+		},
+		{
+			leaf19:   "00000001-00000015-00000003-00000000",
+			flags:    KEYLOCKER | AESKLE | WIDEKL,
+			expected: KeyLocker{Available: true, AESKLE: true, WideKL: true, IWKeyBackup: true, NoBackup: true, RandomIWKey: true, CPL0Only: true},
+		},
+		{
+			// Wide Key Locker is not usable unless AESKLE is set.
+			leaf19:   "00000000-00000004-00000000-00000000",
+			flags:    KEYLOCKER,
+			expected: KeyLocker{Available: true},
+		},
+	} {
+		restore := mockCPU([]byte(`
+CPUID 00000000: 00000019-756E6547-6C65746E-49656E69
+CPUID 00000007: 00000000-00000000-00800000-00000000 [SL 00]
+CPUID 00000019: ` + test.leaf19 + "\n"))
+		Detect()
+		got, flags := CPU.KeyLocker, CPU.ExtFeatures
+		restore()
+		Detect()
+		if got != test.expected {
+			t.Fatalf("leaf 0x19 %s: expected %+v, got %+v", test.leaf19, test.expected, got)
+		}
+		if flags != test.flags {
+			t.Fatalf("leaf 0x19 %s: expected %v, got %v", test.leaf19, test.flags, flags)
+		}
+	}
+}
+
+func TestMockLeaf2Cache(t *testing.T) {
+	for _, test := range []struct {
+		file             string
+		l1i, l1d, l2, l3 int
+	}{
+		{file: "GenuineIntel0000480_486_CPUID.txt", l1i: -1, l1d: -1, l2: -1, l3: -1},
+		{file: "GenuineIntel0000617_P6_CPUID.txt", l1i: 8 << 10, l1d: 8 << 10, l2: 256 << 10, l3: -1},
+		{file: "GenuineIntel0000683_P3_Coppermine_CPUID.txt", l1i: 16 << 10, l1d: 16 << 10, l2: 256 << 10, l3: -1},
+		{file: "GenuineIntel00006D8_PM_Dothan_CPUID.txt", l1i: 32 << 10, l1d: 32 << 10, l2: 2 << 20, l3: -1},
+		// The Pentium 4 L1 instruction cache is a trace cache.
+		{file: "GenuineIntel0000F25_P4_Gallatin_CPUID.txt", l1i: -1, l1d: 8 << 10, l2: 512 << 10, l3: 2 << 20},
+	} {
+		restore := mockFile(t, test.file)
+		got := CPU.Cache
+		restore()
+		if got.L1I != test.l1i || got.L1D != test.l1d || got.L2 != test.l2 || got.L3 != test.l3 {
+			t.Errorf("%s: expected L1I %d, L1D %d, L2 %d, L3 %d, got %+v", test.file, test.l1i, test.l1d, test.l2, test.l3, got)
+		}
+	}
+
+	// 0xFF means the cache information must be read from leaf 4.
+	restore := mockCPU([]byte(`
+CPUID 00000000: 00000002-756E6547-6C65746E-49656E69
+CPUID 00000002: 00FFB001-00000000-00000000-2C04307D
+`))
+	Detect()
+	got := CPU.Cache
+	restore()
+	Detect()
+	if got.L1I != -1 || got.L1D != -1 || got.L2 != -1 || got.L3 != -1 {
+		t.Errorf("expected no caches, got %+v", got)
+	}
+
+	// The low byte of eax is not a descriptor on any query.
+	restore = mockCPU([]byte(`
+CPUID 00000000: 00000002-756E6547-6C65746E-49656E69
+CPUID 00000002: 00000002-00000000-00000000-0000002C
+`))
+	desc, ok := leaf2()
+	restore()
+	Detect()
+	if !ok || len(desc) != 2 || desc[0] != leaf2Descriptors[0x2c] || desc[1] != leaf2Descriptors[0x2c] {
+		t.Errorf("expected the 0x2C descriptor twice, got %+v", desc)
+	}
+}
+
+func TestMockAMDLegacyCache(t *testing.T) {
+	type cache struct{ size, ways int }
+	for _, test := range []struct {
+		file             string
+		l1i, l1d, l2, l3 cache
+	}{
+		{file: "AuthenticAMD0000591_K6_Sharptooth_CPUID.txt", l1i: cache{32 << 10, 2}, l1d: cache{32 << 10, 2}, l2: cache{256 << 10, 4}, l3: cache{-1, 0}},
+		{file: "AuthenticAMD00006A0_K7_Barton_CPUID.txt", l1i: cache{64 << 10, 2}, l1d: cache{64 << 10, 2}, l2: cache{512 << 10, 16}, l3: cache{-1, 0}},
+		{file: "AuthenticAMD0020FB1_K8_Manchester_CPUID.txt", l1i: cache{64 << 10, 2}, l1d: cache{64 << 10, 2}, l2: cache{512 << 10, 16}, l3: cache{-1, 0}},
+		{file: "AuthenticAMD0100F21_K10_Barcelona_CPUID.txt", l1i: cache{64 << 10, 2}, l1d: cache{64 << 10, 2}, l2: cache{512 << 10, 16}, l3: cache{2 << 20, 32}},
+		{file: "AuthenticAMD0100F42_K10_Deneb_CPUID.txt", l1i: cache{64 << 10, 2}, l1d: cache{64 << 10, 2}, l2: cache{512 << 10, 16}, l3: cache{6 << 20, 48}},
+		{file: "AuthenticAMD0100F91_K10_MagnyCours_CPUID.txt", l1i: cache{64 << 10, 2}, l1d: cache{64 << 10, 2}, l2: cache{512 << 10, 16}, l3: cache{10 << 20, 96}},
+		{file: "AuthenticAMD0500F01_K14_Bobcat_CPUID.txt", l1i: cache{32 << 10, 2}, l1d: cache{32 << 10, 8}, l2: cache{512 << 10, 16}, l3: cache{-1, 0}},
+	} {
+		restore := mockFile(t, test.file)
+		c := CPU.Cache
+		restore()
+		got := []cache{{c.L1I, c.L1IWays}, {c.L1D, c.L1DWays}, {c.L2, c.L2Ways}, {c.L3, c.L3Ways}}
+		expected := []cache{test.l1i, test.l1d, test.l2, test.l3}
+		for i, name := range []string{"L1I", "L1D", "L2", "L3"} {
+			if got[i] != expected[i] {
+				t.Errorf("%s %s: expected %+v, got %+v", test.file, name, expected[i], got[i])
+			}
+		}
+	}
+}
+
+func TestMockPadLock(t *testing.T) {
+	restore := mockFile(t, "CentaurHauls00006F8_CNB_Isaiah_CPUID.txt")
+	got := CPU.ExtFeatures
+	vendor := CPU.VendorID
+	restore()
+	if vendor != VIA {
+		t.Fatalf("expected VIA, got %v", vendor)
+	}
+	expected := PADLOCKRNG | PADLOCKRNGEN | PADLOCKACE | PADLOCKACEEN | PADLOCKACE2 |
+		PADLOCKPHE | PADLOCKPHEEN | PADLOCKPMM | PADLOCKPMMEN
+	if got != expected {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	// Without leaf 0xB the core counts come from the OS, which is not available here.
+	sysfs := sysfsRoot
+	sysfsRoot = ""
+	restore = mockFile(t, "CentaurHauls00006FC_CNQ_Isaiah_CPUID.txt")
+	logical, physical, tpc := CPU.LogicalCores, CPU.PhysicalCores, CPU.ThreadsPerCore
+	restore()
+	sysfsRoot = sysfs
+	if logical != 0 || physical != 0 || tpc != 1 {
+		t.Fatalf("unexpected cores: logical %d, physical %d, threads per core %d", logical, physical, tpc)
+	}
+}
+
+func TestMockZhaoxin(t *testing.T) {
+	restore := mockCPU([]byte(`
+CPUID 00000000: 0000000B-68532020-20206961-68676E61
+CPUID 00000001: 000107B5-00080800-00000000-10000000
+CPUID 00000004: 00000000-00000000-00000000-00000000
+CPUID 0000000B: 00000001-00000002-00000100-00000000 [SL 00]
+CPUID 0000000B: 00000004-00000008-00000201-00000000 [SL 01]
+CPUID C0000000: C0000001-00000000-00000000-00000000
+CPUID C0000001: 00000000-00000000-00000000-000003CC
+`))
+	Detect()
+	c := CPU
+	restore()
+	Detect()
+	if c.VendorID != Zhaoxin || c.VendorString != "  Shanghai  " {
+		t.Fatalf("expected Zhaoxin, got %v (%q)", c.VendorID, c.VendorString)
+	}
+	if c.ThreadsPerCore != 2 || c.LogicalCores != 8 || c.PhysicalCores != 4 || !c.HTT() {
+		t.Fatalf("unexpected cores: logical %d, physical %d, threads per core %d, HTT %v", c.LogicalCores, c.PhysicalCores, c.ThreadsPerCore, c.HTT())
+	}
+	expected := PADLOCKRNG | PADLOCKRNGEN | PADLOCKACE | PADLOCKACEEN | PADLOCKACE2 | PADLOCKACE2EN
+	if c.ExtFeatures != expected {
+		t.Fatalf("expected %v, got %v", expected, c.ExtFeatures)
+	}
+}
+
+func TestMockHygon(t *testing.T) {
+	restore := mockFile(t, "HygonGenuine0900F02_Hygon_CPUID.txt")
+	c := CPU
+	restore()
+	if c.VendorID != Hygon || c.Family != 0x18 {
+		t.Fatalf("expected Hygon family 0x18, got %v family %#x", c.VendorID, c.Family)
+	}
+	if c.ThreadsPerCore != 2 || c.LogicalCores != 16 || c.PhysicalCores != 8 || !c.HTT() {
+		t.Fatalf("unexpected cores: logical %d, physical %d, threads per core %d, HTT %v", c.LogicalCores, c.PhysicalCores, c.ThreadsPerCore, c.HTT())
+	}
+	if c.Cache.L1I != 64<<10 || c.Cache.L1D != 32<<10 || c.Cache.L2 != 512<<10 || c.Cache.L3 != 8<<20 {
+		t.Fatalf("unexpected caches: %+v", c.Cache)
+	}
+	if !c.Popcnt() || !c.SVM() || !c.SVMFeatures.NPT {
+		t.Fatalf("expected POPCNT and SVM with NPT, got %v / %v / %+v", c.Features, c.AmdFeatures, c.SVMFeatures)
+	}
+
+	// Hygon implements the AMD memory encryption leaf.
+	restore = mockCPU([]byte(`
+CPUID 00000000: 00000001-6F677948-656E6975-6E65476E
+CPUID 00000001: 00900F02-00100800-74D83209-178BFBFF
+CPUID 80000000: 8000001F-6F677948-656E6975-6E65476E
+CPUID 8000001F: 0000000F-0000016F-0000000F-00000001
+`))
+	Detect()
+	sev := CPU.SEV
+	restore()
+	Detect()
+	if !sev.SME || !sev.SEV || !sev.SEVES || sev.CBitPosition != 47 || sev.NumEncryptedGuests != 15 {
+		t.Fatalf("unexpected SEV: %+v", sev)
+	}
+}
+
+func TestMockAMDThreadsPerCore(t *testing.T) {
+	for _, test := range []struct {
+		file string
+		tpc  int
+	}{
+		// Family 17h reports threads per core in 0x8000001E.
+		{file: "AuthenticAMD0800F12_K17_Zen_CPUID.txt", tpc: 2},
+		// Family 15h reports cores per compute unit in the same field.
+		{file: "AuthenticAMD0600F01_K15_Bulldozer_CPUID.txt", tpc: 1},
+	} {
+		restore := mockFile(t, test.file)
+		got := CPU.ThreadsPerCore
+		restore()
+		if got != test.tpc {
+			t.Errorf("%s: expected %d threads per core, got %d", test.file, test.tpc, got)
+		}
+	}
+}
+
+func TestMockVendorExtras(t *testing.T) {
+	restore := mockFile(t, "GenuineTMx860000543_Crusoe_CPUID.txt")
+	c := CPU
+	restore()
+	if c.VendorExtras == nil || c.VendorExtras.Transmeta == nil {
+		t.Fatal("expected Transmeta information")
+	}
+	expected := TransmetaInfo{
+		HardwareRevision: "1.5.0.2",
+		CMSRevision:      "4.4.3-10-184",
+		NominalMHz:       1000,
+		LongRun:          true,
+		Info:             "20030618 15:27 official release 4.4.3#1",
+	}
+	if *c.VendorExtras.Transmeta != expected {
+		t.Fatalf("expected %+v, got %+v", expected, *c.VendorExtras.Transmeta)
+	}
+	if c.Hz != 1000000000 {
+		t.Fatalf("expected 1GHz, got %d", c.Hz)
+	}
+
+	restore = mockFile(t, "GenuineTMx860000F24_Efficeon_CPUID.txt")
+	c = CPU
+	restore()
+	if c.VendorExtras == nil || c.VendorExtras.Transmeta == nil || c.VendorExtras.Transmeta.HardwareRevision != "24C01101" {
+		t.Fatalf("unexpected Transmeta information: %+v", c.VendorExtras)
+	}
+
+	for _, test := range []struct {
+		file   string
+		vendor Vendor
+		name   string
+	}{
+		{file: "CyrixInstead0000520_6x86_CPUID.txt", vendor: Cyrix, name: "Cyrix 6x86"},
+		{file: "CyrixInstead0000600_MII_CPUID.txt", vendor: Cyrix, name: "Cyrix 6x86MX/MII"},
+		{file: "Geode by NSC0000540_Geode_GX1_CPUID.txt", vendor: NSC, name: "NSC Geode GX1"},
+		{file: "SiS SiS SiS 0000505_SiS550_CPUID.txt", vendor: SiS, name: "SiS 55x"},
+		{file: "RiseRiseRise0000580_mP6II_CPUID.txt", vendor: SiS, name: "Rise mP6 II"},
+		{file: "Vortex86 SoC0000522_Vortex86DX_CPUID.txt", vendor: SiS, name: "Vortex86DX"},
+		{file: "Vortex86 SoC0000586_Vortex86MX_CPUID.txt", vendor: SiS, name: "Vortex86MX"},
+		{file: "Genuine  RDC0000586_RDC_CPUID.txt", vendor: RDC, name: "RDC SoC"},
+	} {
+		restore := mockFile(t, test.file)
+		c := CPU
+		restore()
+		if c.VendorID != test.vendor {
+			t.Errorf("%s: expected vendor %v, got %v", test.file, test.vendor, c.VendorID)
+		}
+		if c.VendorExtras == nil || c.VendorExtras.ModelName != test.name {
+			t.Errorf("%s: expected model name %q, got %+v", test.file, test.name, c.VendorExtras)
+		}
+		if c.BrandName == "unknown" {
+			t.Errorf("%s: expected brand name", test.file)
+		}
+	}
+
+	restore = mockFile(t, "GenuineIntel00906EA_Coffeelake_CPUID.txt")
+	c = CPU
+	restore()
+	if c.VendorExtras != nil {
+		t.Fatalf("unexpected vendor extras: %+v", c.VendorExtras)
+	}
+}
+
+func TestMockRegisterVendor(t *testing.T) {
+	names := append([]string(nil), vendorNames...)
+	defer func() {
+		vendorNames = names
+		delete(vendorMapping, "ExampleVndr1")
+		delete(vendorMapping, "ExampleVndr2")
+	}()
+
+	v := RegisterVendor("ExampleVndr1", "Example")
+	if v.String() != "Example" || ParseVendor("example") != v {
+		t.Fatalf("unexpected registered vendor %v", v)
+	}
+	if v2 := RegisterVendor("ExampleVndr2", "Example"); v2 != v {
+		t.Fatalf("expected %v for existing name, got %v", v, v2)
+	}
+	if v3 := RegisterVendor("ExampleVndr2", "Intel"); v3 != Intel {
+		t.Fatalf("expected Intel, got %v", v3)
+	}
+
+	// "ExampleVndr1" in EBX, EDX, ECX.
+	restore := mockCPU([]byte(`
+CPUID 00000000: 00000001-6D617845-3172646E-56656C70
+CPUID 00000001: 00000000-00000000-00000000-00000000
+`))
+	Detect()
+	got := CPU.VendorID
+	restore()
+	Detect()
+	if got != v {
+		t.Fatalf("expected %v, got %v", v, got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for short signature")
+		}
+	}()
+	RegisterVendor("short", "Short")
+}
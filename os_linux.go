@@ -0,0 +1,145 @@
+// Copyright (c) 2015 Klaus Post, released under MIT License. See LICENSE file.
+
+//+build linux
+
+package cpuid
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Roots of the pseudo filesystems used for OS specific detection.
+// These are replaced in tests.
+var (
+	procRoot  = "/proc"
+	devRoot   = "/dev"
+	sysfsRoot = "/sys"
+)
+
+// osFileExists returns whether the named file exists below root.
+func osFileExists(root, name string) bool {
+	_, err := os.Stat(filepath.Join(root, name))
+	return err == nil
+}
+
+// osCPUFlags returns the flags reported by the kernel for the first cpu in /proc/cpuinfo.
+// nil is returned if the information cannot be read.
+func osCPUFlags() map[string]bool {
+	f, err := os.Open(filepath.Join(procRoot, "cpuinfo"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, "flags") {
+			continue
+		}
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			continue
+		}
+		flags := make(map[string]bool)
+		for _, fl := range strings.Fields(line[i+1:]) {
+			flags[fl] = true
+		}
+		return flags
+	}
+	return nil
+}
+
+// osSGXTotalBytes returns the EPC memory available to the kernel, summed over all NUMA nodes.
+func osSGXTotalBytes() uint64 {
+	files, err := filepath.Glob(filepath.Join(sysfsRoot, "devices/system/node/node*/x86/sgx_total_bytes"))
+	if err != nil {
+		return 0
+	}
+	var total uint64
+	for _, fn := range files {
+		b, err := ioutil.ReadFile(fn)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			continue
+		}
+		total += n
+	}
+	return total
+}
+
+// osReadMSR reads a model specific register of the first CPU through the msr driver.
+// This requires the msr module to be loaded and usually root privileges.
+func osReadMSR(msr uint32) (uint64, bool) {
+	f, err := os.Open(filepath.Join(devRoot, "cpu/0/msr"))
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	var b [8]byte
+	if _, err := f.ReadAt(b[:], int64(msr)); err != nil {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(b[:]), true
+}
+
+// osReadString returns the trimmed content of the named file below root.
+func osReadString(root, name string) (string, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(b)), true
+}
+
+// osCoreCount returns the number of logical and physical cores in the package of cpu0,
+// as reported by the kernel topology in sysfs.
+// Zeros are returned if the topology cannot be read.
+func osCoreCount() (logical, physical int) {
+	pkg, ok := osReadString(sysfsRoot, "devices/system/cpu/cpu0/topology/physical_package_id")
+	if !ok {
+		return 0, 0
+	}
+	dirs, _ := filepath.Glob(filepath.Join(sysfsRoot, "devices/system/cpu/cpu[0-9]*/topology"))
+	cores := make(map[string]bool)
+	for _, dir := range dirs {
+		if p, ok := osReadString(dir, "physical_package_id"); !ok || p != pkg {
+			continue
+		}
+		id, ok := osReadString(dir, "core_id")
+		if !ok {
+			continue
+		}
+		logical++
+		cores[id] = true
+	}
+	return logical, len(cores)
+}
+
+// osAuxv returns the auxiliary vector of the process.
+// nil is returned if it cannot be read.
+func osAuxv() []byte {
+	b, err := ioutil.ReadFile(filepath.Join(procRoot, "self/auxv"))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// osPrctl calls prctl with option and a single argument.
+// The result is only valid if ok is true.
+// It is replaced in tests.
+var osPrctl = func(option, arg uintptr) (r uintptr, ok bool) {
+	r, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, option, arg, 0)
+	return r, errno == 0
+}
@@ -0,0 +1,449 @@
+// Copyright (c) 2015 Klaus Post, released under MIT License. See LICENSE file.
+
+//+build linux
+
+package cpuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// mockOS replaces the OS roots with a temporary directory
+// containing the given files.
+// The returned function will restore the previous roots.
+func mockOS(t *testing.T, files map[string]string) func() {
+	dir, err := ioutil.TempDir("", "cpuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		fn := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldProc, oldDev, oldSys := procRoot, devRoot, sysfsRoot
+	procRoot = filepath.Join(dir, "proc")
+	devRoot = filepath.Join(dir, "dev")
+	sysfsRoot = filepath.Join(dir, "sys")
+	return func() {
+		procRoot, devRoot, sysfsRoot = oldProc, oldDev, oldSys
+		os.RemoveAll(dir)
+	}
+}
+
+const sevGuestCPU = `
+CPUID 00000000: 00000001-68747541-444D4163-69746E65
+CPUID 00000001: 00A00F11-00000800-80000000-00000000
+CPUID 80000000: 8000001F-68747541-444D4163-69746E65
+CPUID 8000001F: 0000003A-0000416F-000001FD-00000001
+`
+
+// writeMSR writes a model specific register to the mocked msr device.
+func writeMSR(t *testing.T, msr uint32, v uint64) {
+	fn := filepath.Join(devRoot, "cpu/0/msr")
+	if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	if _, err := f.WriteAt(b[:], int64(msr)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSEVGuest(t *testing.T) {
+	for _, test := range []struct {
+		files          map[string]string
+		msr            bool   // SEV_STATUS can be read
+		status         uint64 // SEV_STATUS value
+		sev, es, snp   bool
+		expectedActive bool
+	}{
+		{files: nil},
+		{files: map[string]string{"proc/cpuinfo": "processor\t: 0\nflags\t\t: fpu sev\n"}, sev: true, expectedActive: true},
+		{files: map[string]string{"proc/cpuinfo": "processor\t: 0\nflags\t\t: fpu sev sev_es\n"}, sev: true, es: true, expectedActive: true},
+		{files: map[string]string{"dev/sev-guest": ""}, sev: true, es: true, snp: true, expectedActive: true},
+		// SNP guest without the sev-guest driver loaded.
+		{files: map[string]string{"proc/cpuinfo": "processor\t: 0\nflags\t\t: fpu sev sev_es sev_snp\n"}, sev: true, es: true, snp: true, expectedActive: true},
+		// SEV_STATUS takes precedence over the kernel flags.
+		{files: map[string]string{"proc/cpuinfo": "processor\t: 0\nflags\t\t: fpu sev sev_es sev_snp\n"}, msr: true, status: 1, sev: true, expectedActive: true},
+		{msr: true, status: 7, sev: true, es: true, snp: true, expectedActive: true},
+		{msr: true, status: 0},
+	} {
+		restoreOS := mockOS(t, test.files)
+		if test.msr {
+			writeMSR(t, msrSEVStatus, test.status)
+		}
+		restore := mockCPU([]byte(sevGuestCPU))
+		Detect()
+		s, cc := CPU.SEV, CPU.ConfidentialComputing()
+		restore()
+		restoreOS()
+		Detect()
+		if !s.SEV || !s.SEVES || !s.SEVSNP || !s.VMPL || s.SME {
+			t.Fatalf("unexpected SEV features: %+v", s)
+		}
+		if s.CBitPosition != 47 || s.PhysAddrReduction != 5 || s.NumVMPL != 4 {
+			t.Fatalf("unexpected SEV parameters: %+v", s)
+		}
+		if s.NumEncryptedGuests != 509 || s.MinSEVASID != 1 {
+			t.Fatalf("unexpected SEV guests: %+v", s)
+		}
+		if s.GuestSEV != test.sev || s.GuestSEVES != test.es || s.GuestSEVSNP != test.snp || s.Active() != test.expectedActive {
+			t.Fatalf("files %v: unexpected guest state: %+v", test.files, s)
+		}
+		if cc.Active != test.expectedActive {
+			t.Fatalf("files %v: unexpected report: %+v", test.files, cc)
+		}
+		switch {
+		case test.snp && cc.Technology != CCSEVSNP,
+			test.es && !test.snp && cc.Technology != CCSEVES,
+			test.sev && !test.es && cc.Technology != CCSEV,
+			!test.sev && cc.Technology != CCSEVSNP:
+			t.Fatalf("files %v: unexpected technology: %v", test.files, cc.Technology)
+		}
+	}
+}
+
+func TestSGXDevices(t *testing.T) {
+	restoreOS := mockOS(t, map[string]string{
+		"dev/sgx_enclave": "",
+		"sys/devices/system/node/node0/x86/sgx_total_bytes": "100663296\n",
+		"sys/devices/system/node/node1/x86/sgx_total_bytes": "96468992\n",
+	})
+	restore := mockFile(t, "GenuineIntel00706E5_IceLakeY_CPUID.txt")
+	s := CPU.SGX
+	restoreOS()
+	restore()
+	if !s.EnclaveDevice || s.ProvisionDevice {
+		t.Fatalf("unexpected SGX devices: %+v", s)
+	}
+	if s.KernelEPCSize != 100663296+96468992 {
+		t.Fatalf("unexpected kernel EPC size: %d", s.KernelEPCSize)
+	}
+}
+
+func TestCentaurCores(t *testing.T) {
+	files := map[string]string{}
+	for cpu, topo := range [][2]string{{"0", "0"}, {"0", "1"}, {"0", "2"}, {"0", "3"}, {"1", "0"}} {
+		dir := fmt.Sprintf("sys/devices/system/cpu/cpu%d/topology/", cpu)
+		files[dir+"physical_package_id"] = topo[0] + "\n"
+		files[dir+"core_id"] = topo[1] + "\n"
+	}
+	restoreOS := mockOS(t, files)
+	restore := mockFile(t, "CentaurHauls00006FC_CNQ_Isaiah_CPUID.txt")
+	logical, physical, tpc := CPU.LogicalCores, CPU.PhysicalCores, CPU.ThreadsPerCore
+	restoreOS()
+	restore()
+	if logical != 4 || physical != 4 || tpc != 1 {
+		t.Fatalf("unexpected cores: logical %d, physical %d, threads per core %d", logical, physical, tpc)
+	}
+}
+
+func TestSysFeaturesOS(t *testing.T) {
+	const cpu = `
+CPUID 00000000: 00000007-756E6547-6C65746E-49656E69
+CPUID 00000001: 000B06A2-00800800-7FFAFBFF-BFEBFBFF
+CPUID 00000007: 00000000-00000000-00000098-00100000
+`
+	for _, test := range []struct {
+		xcr0     uint32
+		cpuinfo  string
+		expected SysFlags
+	}{
+		{expected: PCID | PKU | CETSS | CETIBT},
+		{xcr0: 0x2e7, expected: PCID | PKU | OSPKE | CETSS | CETIBT},
+		{cpuinfo: "flags\t\t: fpu user_shstk\n", expected: PCID | PKU | CETSS | CETIBT | OSCETSS},
+		{cpuinfo: "flags\t\t: fpu ibt user_shstk\n", expected: PCID | PKU | CETSS | CETIBT | OSCETSS | OSCETIBT},
+	} {
+		restoreOS := mockOS(t, map[string]string{"proc/cpuinfo": test.cpuinfo})
+		restore := mockCPU([]byte(fmt.Sprintf("%sXGETBV 00000000: %08x-00000000\n", cpu, test.xcr0)))
+		Detect()
+		got := CPU.SysFeatures
+		restoreOS()
+		restore()
+		Detect()
+		if got != test.expected {
+			t.Fatalf("expected %v, got %v", test.expected, got)
+		}
+	}
+}
+
+func TestVirtualization(t *testing.T) {
+	const intelCPU = `
+CPUID 00000000: 00000001-756E6547-6C65746E-49656E69
+CPUID 00000001: 000B06A2-00800800-7FFAFBBF-BFEBFBFF
+`
+	const amdCPU = `
+CPUID 00000000: 00000001-68747541-444D4163-69746E65
+CPUID 00000001: 00A00F11-00000800-80000000-00000000
+CPUID 80000000: 8000000A-68747541-444D4163-69746E65
+CPUID 80000001: 00A00F11-00000000-00000004-00000000
+CPUID 8000000A: 00000001-00008000-00000000-00000001
+`
+	for i, test := range []struct {
+		cpu      string
+		files    map[string]string
+		expected VirtualizationInfo
+	}{
+		{cpu: intelCPU, expected: VirtualizationInfo{VMX: true}},
+		{
+			cpu: intelCPU,
+			files: map[string]string{
+				"dev/kvm":                                            "",
+				"sys/module/kvm_intel/parameters/nested":             "Y\n",
+				"sys/module/kvm_intel/parameters/ept":                "Y\n",
+				"sys/module/kvm_intel/parameters/unrestricted_guest": "N\n",
+			},
+			expected: VirtualizationInfo{VMX: true, KVM: true, KVMModule: "kvm_intel", Nested: true, EPT: true},
+		},
+		{
+			cpu: amdCPU,
+			files: map[string]string{
+				"dev/kvm":                              "",
+				"sys/module/kvm_amd/parameters/nested": "1\n",
+				"sys/module/kvm_amd/parameters/npt":    "0\n",
+			},
+			expected: VirtualizationInfo{SVM: true, NPT: true, Hypervisor: true, KVM: true, KVMModule: "kvm_amd", Nested: true},
+		},
+	} {
+		restoreOS := mockOS(t, test.files)
+		restore := mockCPU([]byte(test.cpu))
+		Detect()
+		got := CPU.Virtualization()
+		// The roots can also be given explicitly.
+		sysfs, dev := sysfsRoot, devRoot
+		sysfsRoot, devRoot = "", ""
+		gotRoots := CPU.VirtualizationRoots(sysfs, dev)
+		sysfsRoot, devRoot = sysfs, dev
+		restore()
+		restoreOS()
+		Detect()
+		if got != test.expected {
+			t.Fatalf("test %d: expected %+v, got %+v", i, test.expected, got)
+		}
+		if gotRoots != got {
+			t.Fatalf("test %d: expected %+v from roots, got %+v", i, got, gotRoots)
+		}
+		if got.CanHostVMs() != test.expected.KVM {
+			t.Fatalf("test %d: unexpected CanHostVMs %v", i, got.CanHostVMs())
+		}
+	}
+}
+
+// auxv returns an auxiliary vector with the given type and value pairs.
+func auxv(pairs ...uint64) string {
+	b := make([]byte, 8*len(pairs))
+	for i, v := range pairs {
+		binary.LittleEndian.PutUint64(b[i*8:], v)
+	}
+	return string(b)
+}
+
+func TestAuxvHWCAP(t *testing.T) {
+	const (
+		hwcap  = 1<<0 | 1<<1 | 1<<3 | 1<<4 | 1<<11 | 1<<30 | 1<<31 // FP, ASIMD, AES, PMULL, CPUID, PACA, PACG
+		hwcap2 = 1<<1 | 1<<16 | 1<<23 | 1<<30 | 1<<37 | 1<<44      // SVE2, RNG, SME, SME_FA64, SME2, HBC
+	)
+	// AT_PAGESZ, AT_HWCAP, AT_HWCAP2, AT_NULL, and a trailing entry.
+	restoreOS := mockOS(t, map[string]string{
+		"proc/self/auxv": auxv(6, 4096, atHWCAP, hwcap, atHWCAP2, hwcap2, 0, 0, atHWCAP, 1<<22),
+	})
+	gotHwcap, gotHwcap2 := parseAuxv(osAuxv())
+	restoreOS()
+	if gotHwcap != hwcap || gotHwcap2 != hwcap2 {
+		t.Fatalf("unexpected AT_HWCAP 0x%x, AT_HWCAP2 0x%x", gotHwcap, gotHwcap2)
+	}
+	if gotHwcap&hwcapCPUID == 0 {
+		t.Fatal("HWCAP_CPUID not detected")
+	}
+	got, gotSme := hwcapFlags(gotHwcap, gotHwcap2)
+	want := FP | ASIMD | AES | PMULL | ARMCPUID | PACA | PACG | SVE2 | RNG | HBC
+	if got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	wantSme := SME | SMEFA64 | SME2
+	if gotSme != wantSme {
+		t.Fatalf("expected %v, got %v", wantSme, gotSme)
+	}
+
+	if runtime.GOARCH == "arm64" {
+		hwcap, hwcap2 := parseAuxv(osAuxv())
+		f, sme := hwcapFlags(hwcap, hwcap2)
+		t.Log("Host AT_HWCAP:", f, "SME:", sme)
+	}
+	if hwcap, hwcap2 := parseAuxv(nil); hwcap != 0 || hwcap2 != 0 {
+		t.Fatal("expected no features from empty auxv")
+	}
+}
+
+func TestArmIdentify(t *testing.T) {
+	for i, test := range []struct {
+		midr   string
+		id     ArmID
+		vendor Vendor
+		brand  string
+		family int
+		model  int
+	}{
+		{
+			// AWS Graviton2
+			midr:   "0x00000000413fd0c1\n",
+			id:     ArmID{Implementer: 0x41, Variant: 3, Architecture: 0xf, PartNum: 0xd0c, Revision: 1},
+			vendor: ARM,
+			brand:  "ARM Neoverse-N1",
+			family: 0xd0c,
+			model:  0x31,
+		},
+		{
+			midr:   "0x00000000461f0010\n",
+			id:     ArmID{Implementer: 0x46, Variant: 1, Architecture: 0xf, PartNum: 0x001, Revision: 0},
+			vendor: Fujitsu,
+			brand:  "Fujitsu A64FX",
+			family: 1,
+			model:  0x10,
+		},
+		{
+			midr:   "0x00000000c00fac30\n",
+			id:     ArmID{Implementer: 0xc0, Architecture: 0xf, PartNum: 0xac3},
+			vendor: Ampere,
+			brand:  "Ampere AmpereOne",
+			family: 0xac3,
+		},
+		{
+			// Unknown part of a known implementer.
+			midr:   "0x00000000410ffff2\n",
+			id:     ArmID{Implementer: 0x41, Architecture: 0xf, PartNum: 0xfff, Revision: 2},
+			vendor: ARM,
+			brand:  "unknown",
+			family: 0xfff,
+			model:  2,
+		},
+		{
+			// Unknown implementer.
+			midr:   "0x00000000ff0f0010\n",
+			id:     ArmID{Implementer: 0xff, Architecture: 0xf, PartNum: 0x001},
+			vendor: Other,
+			brand:  "unknown",
+			family: 1,
+		},
+	} {
+		restoreOS := mockOS(t, map[string]string{
+			"sys/devices/system/cpu/cpu0/regs/identification/midr_el1": test.midr,
+		})
+		c := CPUInfo{BrandName: "unknown"}
+		c.armIdentify(sysfsMidr())
+		restoreOS()
+		if c.ArmID != test.id {
+			t.Errorf("test %d: expected %+v, got %+v", i, test.id, c.ArmID)
+		}
+		if c.VendorID != test.vendor || c.BrandName != test.brand {
+			t.Errorf("test %d: expected %v %q, got %v %q", i, test.vendor, test.brand, c.VendorID, c.BrandName)
+		}
+		if c.Family != test.family || c.Model != test.model {
+			t.Errorf("test %d: expected family 0x%x model 0x%x, got 0x%x 0x%x", i, test.family, test.model, c.Family, c.Model)
+		}
+	}
+
+	restoreOS := mockOS(t, nil)
+	defer restoreOS()
+	if midr := sysfsMidr(); midr != 0 {
+		t.Fatalf("expected no MIDR, got 0x%x", midr)
+	}
+}
+
+// mockPrctlVL returns a prctl that keeps per option vector lengths
+// and picks the largest supported length that is not above the requested one.
+func mockPrctlVL(vl map[uintptr]uintptr, supported map[uintptr][]uintptr) func(option, arg uintptr) (uintptr, bool) {
+	return func(option, arg uintptr) (uintptr, bool) {
+		switch option {
+		case prSVEGetVL, prSMEGetVL:
+			r, ok := vl[option]
+			return r, ok
+		case prSVESetVL, prSMESetVL:
+			get := option + 1
+			lengths, ok := supported[get]
+			if !ok {
+				return 0, false
+			}
+			n := lengths[0]
+			for _, l := range lengths {
+				if l <= arg&prVLLenMask {
+					n = l
+				}
+			}
+			vl[get] = arg&prVLInherit | n
+			return vl[get], true
+		}
+		return 0, false
+	}
+}
+
+func TestArmVectorLength(t *testing.T) {
+	old := osPrctl
+	defer func() { osPrctl = old }()
+	rdvl := func() uint64 { return 16 }
+
+	// Lengths that can be changed. PR_SVE_VL_INHERIT is set in the result
+	// and must be masked, and the original configuration must be restored.
+	vl := map[uintptr]uintptr{prSVEGetVL: prVLInherit | 32, prSMEGetVL: 64}
+	osPrctl = mockPrctlVL(vl, map[uintptr][]uintptr{
+		prSVEGetVL: {16, 32},
+		prSMEGetVL: {16, 32, 64},
+	})
+	got := armVectorLength(true, true, rdvl)
+	want := ArmVectorLength{SVE: 32, SME: 64, SVEPerThread: true, SMEPerThread: true}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if vl[prSVEGetVL] != prVLInherit|32 || vl[prSMEGetVL] != 64 {
+		t.Fatalf("vector lengths not restored: %+v", vl)
+	}
+	if got := armVectorLength(false, false, rdvl); got != (ArmVectorLength{}) {
+		t.Fatalf("expected no vector lengths, got %+v", got)
+	}
+
+	// A single supported length cannot be changed.
+	vl = map[uintptr]uintptr{prSVEGetVL: 16, prSMEGetVL: 64}
+	osPrctl = mockPrctlVL(vl, map[uintptr][]uintptr{
+		prSVEGetVL: {16},
+		prSMEGetVL: {64},
+	})
+	got = armVectorLength(true, true, rdvl)
+	want = ArmVectorLength{SVE: 16, SME: 64}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// Lengths that can be read, but not set.
+	vl = map[uintptr]uintptr{prSVEGetVL: 32, prSMEGetVL: 64}
+	osPrctl = mockPrctlVL(vl, nil)
+	got = armVectorLength(true, true, rdvl)
+	want = ArmVectorLength{SVE: 32, SME: 64}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// Without kernel support the SVE vector length is read with rdvl.
+	osPrctl = func(option, arg uintptr) (uintptr, bool) { return 0, false }
+	got = armVectorLength(true, true, rdvl)
+	want = ArmVectorLength{SVE: 16}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2015 Klaus Post, released under MIT License. See LICENSE file.
+
+//+build !linux
+
+package cpuid
+
+var (
+	procRoot  = ""
+	devRoot   = ""
+	sysfsRoot = ""
+)
+
+func osFileExists(root, name string) bool { return false }
+
+func osCPUFlags() map[string]bool { return nil }
+
+func osSGXTotalBytes() uint64 { return 0 }
+
+func osReadMSR(msr uint32) (uint64, bool) { return 0, false }
+
+func osReadString(root, name string) (string, bool) { return "", false }
+
+func osCoreCount() (logical, physical int) { return 0, 0 }
+
+func osAuxv() []byte { return nil }
+
+var osPrctl = func(option, arg uintptr) (r uintptr, ok bool) { return 0, false }